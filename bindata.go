@@ -10,18 +10,547 @@
 // Directories are treated recursively. The keys of the map are the paths
 // of the files relative to the current directory. A different root for
 // the paths can be specified on the command line (-r).
+// An input of the form "path:prefix" is embedded under a virtual prefix instead:
+// with "dist/:static/", the file dist/app.js is stored under "static/app.js".
+// Inputs whose keys would be absolute or outside of the root (starting with "..")
+// are rejected by default, as they leak the layout of the machine into binaries.
+// With -abs=trim, their keys are relative to the parent directory of the input
+// instead, and with -abs=keep, they are kept as is.
+// With -from-archive, the zip and tar archives (.zip, .tar, .tar.gz and .tgz)
+// provided on the command line are treated as directory trees: their entries
+// are embedded under their paths within the archive.
+// Inputs that are http or https URLs are downloaded at generation time and
+// stored under the last element of their path. The fragment of the URL, which
+// is not sent to the server, can specify another key and pin the SHA-256 of the
+// contents, failing the run if the file changes:
+//  bindata https://example.com/schema.json#key=schemas/v1.json&sha256=9f86d0...
+// The Go source files of packages, e.g. templates for code generators or
+// programs for embedded interpreters, can be embedded by package pattern
+// (-go-pkg, repeatable), as listed by go list, without the test files:
+//  bindata -go-pkg ./templates/... -o sources.go
+// Directories can be layered like theme overrides (-layer, repeatable): the
+// files of each layer are keyed relative to it and override those of the same
+// keys in the previous layers, before the other inputs are added:
+//  bindata -layer assets/default -layer assets/customer -o assets.go
+// If several files end up with the same key, the run fails unless a policy
+// is specified to keep the first or last one (-on-collision=first|last|error).
+// Paths starting with a dash are given after a -- terminator ending the flags:
+//  bindata -o icons.go -- -logo.png
+//
+// Within directories, the files matching the patterns of .bindataignore files
+// are skipped, with the same semantics as .gitignore files. The .gitignore files
+// themselves can be honoured as well (-gitignore).
+// The files of directories can also be skipped by size (-min-size and -max-size,
+// e.g. 10MB) and by age (-max-age, the maximum time since their last modification,
+// e.g. 720h), to exclude stale or oversized artifacts without maintaining ignore
+// lists. The files given on the command line are never skipped.
+//
+// So that secrets do not end up in distributable binaries, the files to embed
+// can be checked for them (-secret-scan=warn|error): the .env files, SSH private
+// keys, credential files and key stores by name, and the private keys, AWS keys,
+// GitHub and Slack tokens and Google API keys by contents. With error, the run
+// fails listing all the files found. Files known to be safe, e.g. test fixtures,
+// are not checked if they match a pattern of -secret-allow (repeatable).
+//
+// Empty files, e.g. placeholders like .keep, are embedded as empty entries,
+// found by all the accessors. They can be skipped instead, wherever they come
+// from (-skip-empty).
+//
+// Symbolic links are followed. The directories linking back to one of their
+// ancestors, through symbolic links or bind mounts, are skipped with a warning,
+// or make the run fail with -strict. The depth of the walk can be limited as
+// well (-max-depth, 1 for the files of the input directories only).
 //
 // By default, the data are saved as byte slices.
-// It is also possible to save them a strings (-s).
+// It is also possible to save them a strings (-s). Their bytes are printed
+// 12 per line (16 for strings), indented by a tab within their value, with
+// hexadecimal digits in lower case: the number of bytes per line (-columns),
+// the indentation (-tab, tabs or spaces, reindented by gofmt) and the case of
+// the digits (-uppercase) can be changed.
 //
 // By default, the package name of the file containing the generate directive
 // is used as the package name of the generated file, or "main" otherwise.
 // A custom package name can also be specified on the command line (-p).
 //
+// Files can be piped through external commands before being embedded (-transform),
+// for example to minify or optimize them. The flag takes the form
+// "[pattern=]command args" and can be repeated; transforms are applied in order
+// to the files whose path (or base name, if the pattern has no slash) matches
+// the glob pattern, where ** matches any number of directories, or to all files
+// if no pattern is given.
+// So that expensive optimizers do not run again on unchanged files, their
+// outputs can be cached in a directory (-transform-cache), keyed by a hash of
+// the command and the contents of the file.
+//
+// So that bundles generated on Windows and Linux machines are identical, the
+// line endings of the text files (valid UTF-8 without NUL bytes) can be
+// converted (-normalize-eol=lf or crlf) and their UTF-8 byte order mark stripped
+// (-strip-bom), after the transforms.
+//
+// To prevent the data from being modified, it can be saved in an unexported map
+// of strings (-readonly) accessed through generated functions. For the default map
+// name, the data is stored in bindataFiles and bindataAsset returns a copy of the
+// contents of a file, while bindataAssetUnsafe returns them without copying,
+// in which case the returned slice must not be modified.
+//
+// The files can be compressed (-compress gzip), in which case the contents
+// of a file must be read with the generated function named after the map with
+// the suffix "Read", which decompresses them if needed. Only the files which
+// benefit from compression are compressed: files in compressed formats (JPEG,
+// PNG, zip...), with a high entropy, or which would not shrink by at least 10%
+// are stored raw. The codec used for each compressed file is recorded in a map
+// (suffix "Codecs").
+//
+// Besides gzip, the files can be compressed as raw DEFLATE streams (-compress
+// flate), without the gzip header and checksum, which matters for small files.
+// With -compress auto, both codecs are tried for each file and the smallest
+// result is kept. Only the codecs of the standard library are supported, so that
+// the generated code has no dependency.
+//
+// To avoid decompressing hot files on the first request, -preload generates
+// functions decompressing compressed files in advance, concurrently and in the
+// background: the named files (suffix "Preload") or all of them (suffix
+// "PreloadAll"), which waits for them or until its context is done. The read
+// function then returns copies of the files decompressed:
+//  bindataPreload("index.html", "app.js")
+//  go bindataPreloadAll(ctx)
+//
+// To keep the memory of applications embedding large, rarely used datasets
+// bounded, the compressed files larger than a size (-spill-over 64MB) are
+// decompressed to a cache directory on first access (a variable with the suffix
+// "SpillDir", by default in the user cache directory) and read from there
+// thereafter, preloaded to disk rather than memory. The files are named after
+// their SHA-256 checksums, which are checked once per process. A function
+// (suffix "Open") opens any file for reading, from disk if spilled:
+//  r, err := bindataOpen("dataset.csv")
+//
+// Large files can be streamed to a writer, e.g. an HTTP response, without
+// copying them in memory: with -writer, a function (suffix "WriteTo") writes
+// the contents of a file to a writer, decompressing them on the fly if needed:
+//  n, err := bindataWriteTo("video.mp4", w)
+//
+// To implement memory budgets or progress bars, -sizes generates functions
+// returning the size of a file once decompressed (suffix "Size") and as stored
+// in the binary (suffix "StoredSize"), -1 for missing files, and the total size
+// of the files (suffix "TotalSize"), without decompressing anything:
+//  if bindataSize("video.mp4") > budget {
+//
+// All the metadata of a file is available at once with -stat, which generates
+// a struct type (suffix "AssetInfo") with the name, size once decompressed,
+// permissions, modification time and SHA-256 checksum of a file, and a function
+// (suffix "Stat") returning it, or false if there is no such file. The
+// permissions and times are those recorded with -meta, 0644 and zero otherwise:
+//  info, ok := bindataStat("index.html")
+// With -git-meta, the descriptions also give the hash, committer date and
+// author of the last git commit of each file, e.g. for asset version displays
+// or cache keys tied to the history of the contents. They are empty for the
+// files which were never committed, or if git is not installed.
+//
+// Large byte slice literals are slow to compile and link. With -bytes-via-string,
+// the data is saved as strings in an unexported map (bindataFiles for the default
+// map name) and a function (suffix "Bytes") returns the contents of a file
+// converted to a byte slice at access time.
+// Alternatively, the files larger than a size (-embed-over 1MB) are stored in a
+// directory next to the output file (bindata_files for bindata.go), embedded
+// with go:embed and added to the map on initialization, so that small files
+// remain literals, free of any file system, and the accessors are the same for
+// all the files. Only the map layout supports it, with an output file (-o)
+// and without -o-copy, -max-mem, -emit or -t.
+//
+// To generate from large directories on machines with little memory, the
+// size of the contents held in memory can be capped (-max-mem 512MB): the
+// files beyond the cap are not read during the generation but streamed from
+// their source files to the output file, which is itself streamed instead of
+// rendered in memory. The files to transform or normalize are always read,
+// with a warning, and the options needing the contents of all the files
+// (-compress, -precompressed, -hash-names, -layout blob, -layout vars,
+// -comments, -bundle-version, -lock, -stat, -emit, -t, groups and -o-for)
+// are rejected.
+//
+// Instead of a map, the files can be saved in a slice sorted by path (-layout slice),
+// searched by a generated function (suffix "Lookup"), which avoids building a map
+// at init for bundles of many small files. For TinyGo and other constrained targets
+// (-target tinygo), where maps are allocated at init, the files are saved as strings
+// in a sorted slice. With -layout blob, all the files are concatenated into a
+// single string constant (suffix "Blob"), which compiles faster than many
+// literals, and the sorted slice holds their offsets and sizes, identical files
+// sharing their data. The contents returned by the lookup function are slices
+// of the blob, without copies or allocations. These layouts do not support -readonly, -bytes-via-string,
+// -compress, -spa, groups and -o-for.
+//
+// Programs using a few files of a shared package of many can let the linker
+// drop the others with -layout vars: each file is stored in its own variable,
+// only referenced by its accessor function, named after the path of the file,
+// e.g. bindataStaticIndexHTML() for "static/index.html" (with the naming
+// strategy of -tree). There is no map to look up files by name, so the options
+// generating functions taking names, such as -fs or -readonly, are rejected,
+// but -tree, -receiver and -const-prefix are supported.
+//
+// By default, the generated code uses the latest features of Go. To support
+// projects pinned to an older release, -lang gives the oldest version of Go the
+// generated code must compile with, e.g.
+//  bindata -lang 1.16 -o assets.go assets
+// The code then avoids the newer language features and functions, and the
+// flags that cannot do without them are rejected: -fs requires Go 1.16 (io/fs),
+// -meta and -readonly require Go 1.20.
+//
+// An HTTP handler serving the files can be generated for single-page applications
+// (-spa): given the name of the index file, the generated function (named after
+// the map with the suffix "Handler") returns a handler serving the file matching
+// the path of each request, or the index file for unknown paths so that
+// client-side routing works.
+//
+// To serve files from custom handlers, -serve-http generates a function (suffix
+// "ServeHTTP") serving the named file with http.ServeContent, so that byte-range
+// requests, e.g. to stream embedded video or audio, and conditional requests work
+// out of the box. The modification times recorded with -meta time are used for
+// the Last-Modified header:
+//  bindataServeHTTP(w, r, "media/intro.mp4")
+//
+// To avoid compressing responses on every request, the gzip encodings of the
+// files which benefit from compression can be stored as well, in a map (suffix
+// "Gzip"), and sent as is to the clients accepting them (-precompressed, which
+// implies -serve-http). Brotli, which has no implementation in the standard
+// library, is not supported.
+//
+// In applications composed of several packages embedding files, each bundle can
+// register itself under a name in the registry of the runtime package of
+// bindata (github.com/simleb/bindata/runtime) when its package is initialized
+// (-register), so that all the bundles can be enumerated and served uniformly:
+//  http.Handle("/assets/", http.StripPrefix("/assets/", runtime.Handler()))
+// The handler serves the file name of the bundle b at the path b/name.
+//
+// To ease migrations from or to the embed package, a file system type with the
+// methods of embed.FS (Open, ReadFile and ReadDir) can be generated (-fs), named
+// after the map with the suffix "FS":
+//  var static fs.FS = bindataFS{}
+// With -walk, which implies -fs, a function (suffix "Walk") traverses the
+// embedded directories like fs.WalkDir traverses directories on disk, fs.SkipDir
+// included:
+//  err := bindataWalk("templates", func(name string, d fs.DirEntry, err error) error { ... })
+//
+// With -iter, a function (suffix "All") returns an iterator over the names and
+// contents of the files sorted by name, for range-over-func loops (Go 1.23),
+// without exposing the map. The contents are copied, and decompressed, as the
+// loop proceeds:
+//  for name, data := range bindataAll() { ... }
+//
+// The permissions of the files can be recorded (-meta exec for the executable
+// bit only, -meta mode for all the permissions) in a map (suffix "Modes"), and a
+// function (suffix "Restore") writes the files to a directory with their
+// permissions, e.g. to extract helper binaries and scripts. On Windows, where
+// files have no executable bit, executables are recognized by their extension
+// (.exe, .com, .bat and .cmd) or a "#!" line.
+//
+// The modification times of the files can be recorded as well (-meta time,
+// e.g. -meta mode,time), in a map (suffix "ModTimes") used by the restore
+// function and the file system. For reproducible builds, the times are clamped
+// to the SOURCE_DATE_EPOCH environment variable if it is set; the generated
+// files contain no other time or nondeterministic field.
+//
+// To record which files are actually used, e.g. to prune the unused ones, a
+// hook (a variable named after the map with the suffix "OnAccess") can be
+// generated (-hook). When set, it is called with the name of each file
+// accessed through the generated functions.
+//
+// Localized variants of files can be named after the convention name.locale.ext,
+// e.g. messages.en.json and messages.fr.json. With -localized, given the default
+// locale, a function (suffix "Localized") returns the best variant of a file for
+// a locale, trying the locale ("fr-CA"), its less specific forms ("fr"), the
+// default locale and finally the file itself:
+//  data, ok := bindataLocalized("messages.json", "fr-CA")
+//
+// Files such as configurations can be consumed as typed values with -typed,
+// which generates a generic function (suffix "AssetAs", Go 1.18) decoding a file
+// with the given function, along with built-in decoders of the comma-separated
+// formats: json (suffix "JSON") and yaml (suffix "YAML", using gopkg.in/yaml.v3),
+// or none. Each file is decoded once per type and the values are cached:
+//  config, err := bindataAssetAs("config.json", bindataJSON[Config])
+//
+// The Go templates embedded (.tmpl and .gotmpl files) can be parsed by a
+// generated function (suffix "Templates") into a tree of templates named after
+// their keys, with text/template or html/template (-templates text|html).
+// They are parsed once, on the first call, with the functions of a variable
+// (suffix "TemplateFuncs") set beforehand:
+//  t, err := bindataTemplates()
+//  err = t.ExecuteTemplate(w, "pages/index.tmpl", data)
+//
+// To embed SQL migrations, -migrations keeps only the .sql files and generates
+// their list by increasing version (suffix "Migrations"), from file names such as
+// 0001_create_users.up.sql and 0001_create_users.down.sql (or 0001_create_users.sql
+// alone). It implies -fs, so that migration libraries reading an fs.FS can use
+// the generated file system.
+//
+// To patch files in production without a rebuild, -override names an
+// environment variable, e.g. -override BINDATA_OVERRIDE_DIR. When it is set to a
+// directory or a zip archive, the files it contains replace the embedded files
+// with the same names, which remain the fallback. The replacement applies to the
+// generated accessor (suffix "Data") and the functions built on it, such as the
+// file system; the directory or archive is opened on first use (suffix "Override").
+// So that only trusted bundles replace the files, e.g. for A/B tests of asset
+// bundles without rebuilds, -override-key takes a hex-encoded Ed25519 public key:
+// only zip archives signed with the matching private key, whose signature is
+// read from the file with the extension ".sig" next to the archive, are then
+// used, the embedded files remaining the fallback. Keys are generated and
+// archives signed by:
+//  bindata sign -genkey -key bundle.key
+//  bindata sign -key bundle.key bundle.zip
+//
+// Constants holding the file names can be generated along with the map
+// by specifying a prefix for their names (-const-prefix). For instance, with
+// the prefix "Asset", the constant for "static/index.html" is AssetStaticIndexHTML.
+// Code referring to files through these constants fails to compile when a file
+// is renamed or removed.
+//
+// Accessors matching the failure policy of a team can be generated with
+// -accessors, a comma-separated list of: error, for a function (suffix "Asset")
+// returning an error if there is no such file, replacing that of -readonly;
+// panic, for a function (suffix "MustAsset") panicking instead; and const,
+// restricting their argument to a type (suffix "Name") of the constants of
+// -const-prefix, so that a name computed at run time fails to compile:
+//  data := bindataMustAsset(AssetStaticIndexHTML)
+//
+// The accessors returning errors (or panicking) use typed errors, so that
+// callers can tell missing files from decoding failures programmatically, e.g.
+// to render their own, translated, messages: a missing file is reported as a
+// value of a type with the suffix "ErrAssetNotFound", holding its name and
+// matching os.ErrNotExist, and a file which cannot be decompressed or decoded
+// (-typed) as a value of a type with the suffix "ErrDecode", wrapping the error
+// of the decoder:
+//  var nf bindataErrAssetNotFound
+//  if errors.As(err, &nf) { ... nf.Name ... }
+//
+// For autocompletion over the embedded tree, -tree generates a variable of the
+// given name whose fields are the directories and whose methods return the
+// contents of the files, e.g. with -tree Assets:
+//  page := Assets.Templates.IndexHTML() // "templates/index.html"
+// The identifiers keep the common initialisms in upper case, unless the plain
+// naming strategy is chosen (-tree-naming plain, IndexHtml). Path elements
+// starting with a digit are prefixed with "X", and the run fails if two
+// elements of a directory map to the same identifier.
+//
+// To keep the accessors flat instead, -receiver generates an empty struct type
+// of the given name with a method per file, named after its whole path, e.g.
+// with -receiver Assets:
+//  page := Assets{}.TemplatesIndexHTML() // "templates/index.html"
+// The methods of the files which are not used are dropped by the linker. They
+// follow the naming strategy of -tree, and the run fails if two files map to
+// the same identifier.
+//
+// A size budget for the embedded files can be set on the command line (-budget).
+// Budgets for individual directories (relative to the root of the map keys) can
+// be set in a JSON configuration file (-c):
+//
+//	{
+//		"budget": "10MB",
+//		"budgets": {"static/img": "2MB", "templates": "500KB"}
+//	}
+//
+// If a budget is exceeded, the run fails with a report of the largest files.
+//
+// The configuration file can also define profiles, e.g. for dev and prod
+// builds, selected on the command line (-profile prod). The inputs of a profile
+// are added to those of the command line, and its compression and output file
+// apply unless -compress or -o is set:
+//
+//	{
+//		"profiles": {
+//			"dev": {"inputs": ["mocks"], "output": "data_dev.go"},
+//			"prod": {"inputs": ["seeds"], "compress": "gzip", "output": "data.go"}
+//		}
+//	}
+//
+// To catch size regressions in continuous integration, the sizes of the files
+// can be compared to a baseline manifest committed with the code:
+//  bindata budget-check -baseline sizes.json [-max-growth 5%] [-max-file-growth 10KB] [flags] [paths...]
+// It prints the total and the files which grew beyond the limits, relative or
+// absolute, new files included, and fails if there are any. With -update, the
+// current sizes are written to the baseline instead. The flags affecting the
+// keys (-r, -abs, -from-archive, -gitignore) are those of a normal run.
+//
+// To guard against unexpected changes of vendored third-party assets, the
+// SHA-256 checksums of the files can be pinned in a lockfile committed with the
+// code (-lock bindata.lock), with a line per file in the format of sha256sum.
+// The run fails if a file changed, is not pinned or is missing, unless the
+// lockfile is updated with the current checksums (-update-lock):
+//  bindata -lock bindata.lock -update-lock -o assets.go vendor/assets
+// The checksums are those of the contents embedded, once transformed.
+//
+// Files can also be assigned to groups in the configuration file, each group
+// being emitted into its own file (named after the output file with the group
+// name as suffix) built only with the build tag named after the group:
+//
+//	{
+//		"groups": {"premium": ["premium/**"], "demo_content": ["demo/**", "*.sample"]}
+//	}
+//
+// Patterns without a slash are matched against the base names of the files
+// and "**" matches any number of directories.
+//
+// So that legacy paths keep working after assets are reorganized, the
+// configuration file can declare aliases of files:
+//
+//	{
+//		"aliases": {"favicon.ico": "static/favicon.ico"}
+//	}
+//
+// They are listed in a map (suffix "Aliases") and added to the map on
+// initialization, sharing the contents of their files rather than duplicating
+// them, so that all the accessors find them. Only the map layout supports
+// them, without -hash-names or -obfuscate-keys.
+//
+// So that a team generates identical output, the configuration file can pin
+// the version of bindata: the pin subcommand records the running version (or
+// a given one) in the configuration file, -c bindata.json by default, and the
+// generation then fails with another version, explaining how to install the
+// pinned one, which pin -install does with go install:
+//  bindata pin -c bindata.json
+//  bindata pin -install -c bindata.json
+// The development builds, whose version is unknown, only warn.
+//
+// For cache busting, the files can be stored under content-addressed keys
+// (-hash-names): a hash of the contents is inserted before the extension,
+// e.g. "app.js" is stored as "app.3f9ab2c1.js". A second map (named after the
+// map with the suffix "Hashed") gives the key of each file from its name, and
+// a function (suffix "Rewrite") replaces the file names referenced in a text,
+// such as an HTML page or a style sheet, with their keys. Constants generated
+// with -const-prefix hold the content-addressed keys.
+// So that frontend tooling references the same assets as the Go code, the
+// names of the files by path can be written as JSON (-json-manifest assets.json)
+// and as TypeScript declarations (-ts-manifest assets.d.ts) of a union type
+// of the paths (AssetPath) and of the default export of the JSON manifest.
+//
+// So that the names of the files do not appear in binaries, e.g. for closed-source
+// tools, they can be replaced by hashes salted with a given string
+// (-obfuscate-keys salt). A function (suffix "Key") hashes a name at run time
+// and another (suffix "Get") returns the contents of the named file:
+//  data, ok := bindataGet("config/defaults.json")
+// The options generating code or comments that refer to the names, such as -fs
+// or -comments, cannot be combined with it.
+//
+// A constant fingerprinting the names and contents of all the files (named after
+// the map with the suffix "Version") can be generated with -bundle-version,
+// e.g. to build cache keys or ETags for the whole bundle.
+//
+// The contents of the files are hashed concurrently, once for all the uses.
+// The hashes of content-addressed keys and bundle versions, which are cache
+// keys, can be XXH64 rather than SHA-256 (-cache-hash xxhash), which is much
+// faster on large bundles but not collision resistant. The checksums verifying
+// integrity (-lock, -comments, -stat) are always SHA-256.
+//
+// With -doc, the package documentation of the generated file lists the files
+// with their sizes, so that go doc shows the contents of the package.
+//
+// Each file can be preceded by a comment giving its source path, its size and
+// its SHA-256 hash (-comments), so that changes are easy to review. In the
+// comments, the names which are not valid UTF-8 or hold characters which are
+// not printable, such as newlines, are quoted as Go strings. JSON cannot
+// represent the names which are not valid UTF-8: they fail -emit json.
+//
+// The generated files start with the comment recognized by Go tools as marking
+// generated code ("// Code generated by bindata. DO NOT EDIT."), which can be
+// changed (-generated).
+//
+// The contents of files can be inserted at the top (-header) and at the end
+// (-footer) of the generated files, e.g. for license boilerplate, linter
+// directives or code generation markers.
+//
 // The output file can be specified on the command line (-o).
-// If a file already exists at this location, it will be overwritten.
+// If a file already exists at this location, it will be overwritten, unless it
+// looks written by hand: the output files, copies and group files are only
+// overwritten if they are empty or carry a comment marking generated files,
+// which guards against a typo in -o. -force overwrites them regardless, and the
+// check is skipped if the comment is disabled (-generated "").
+// Output files found among the inputs, e.g. when generating into an input
+// directory, are skipped with a warning, or make the run fail with -strict.
+// The file is written atomically: it is only replaced once generation succeeds.
+// It is not written at all if its contents are unchanged, so that build tools
+// relying on modification times do not see it change after every go generate
+// (-force-write writes it regardless).
 // The file produced is properly formatted and commented.
 // If no output file is specified, the contents are printed on the standard output.
+// To pipe the output into gofmt or other filters from scripts, -o - makes it
+// explicit and quiet: the warnings and reports are discarded and the errors are
+// printed on the standard error, so that nothing but the generated code is
+// written to the standard output.
+//
+// Identical copies of the output file, e.g. for an artifacts directory, are
+// written in the same pass with -o-copy, which can be repeated:
+//
+//  bindata -o assets.go -o-copy artifacts/assets.go assets
+//
+// To embed the same files into programs written in other languages, sharing
+// the walk and the filters, the output can be a C header (-emit c), with an
+// array of unsigned char per file and a table (named after the map) of their
+// names, data and sizes ended by a NULL name, or a JSON object mapping the
+// name of the map to an object of the contents of the files encoded in base64
+// (-emit json). The options of the generated Go code do not apply to them, and
+// -compress, -hash-names, -obfuscate-keys, -only, groups and -o-for are rejected.
+//
+// For other formats, the output can be rendered with a custom text/template
+// (-t assets.tmpl), under the same restrictions. Its data has the comment
+// marking generated files (.Generated), which the template must print so that
+// the output can be overwritten, unless there is none (-generated ""), the name
+// of the map (.Name) and the files sorted by key (.Files), each with its key
+// (.Key) and contents (.Data). Besides
+// the functions of text/template, camelcase converts a key to a Go identifier,
+// sha256 returns the hexadecimal SHA-256 checksum of contents and base64 their
+// standard base64 encoding:
+//
+//  {{.Generated}}
+//  {{range .Files}}{{camelcase .Key}} {{sha256 .Data}} {{base64 .Data}}
+//  {{end}}
+//
+// To update some files of an existing output file without reading all the
+// inputs again, the files to update can be selected by patterns (-only,
+// repeatable): only the inputs matching them are read, and the other files
+// are kept from the existing output file, whatever its layout.
+//  bindata -o assets.go -only 'templates/**' assets
+//
+// So that a refactor of the source directories cannot silently drop embedded
+// files, an existing output file can be regenerated with exactly the same keys
+// (-mirror): the inputs are the files named by its keys, resolved against the
+// root of the keys (-r), and the run fails if any of them is missing or
+// skipped.
+//  bindata -mirror assets.go -o assets.go -r assets
+//
+// The files matching a pattern can be written to another file (-o-for), e.g.
+//  -o-for 'templates/**=templates_gen.go' -o-for 'static/**=static_gen.go'
+// The map and the generated functions are declared in the main output file (-o),
+// and the other files add their files to the map when the package is initialized.
+// The first matching pattern applies, with the same syntax as the patterns of groups.
+//
+// With -per-dir-output, each directory given on the command line gets its own
+// generated file (named after -o, "bindata.go" by default) written inside it.
+// The keys are relative to the directory and the package name is inferred
+// from the Go files of the directory, or from its name if there are none.
+//
+// The files embedded can be reported on the standard error with their sizes
+// and running totals (-v), as well as the duration of each phase of the
+// generation: walk, read, encode and write (-progress).
+// With -v, a summary follows: the number of files, their total size, the size
+// stored after compression and an estimate of their contribution to the size of
+// binaries. To track asset bloat over time, the summary can also be written as
+// JSON with the largest files (-report report.json).
+//
+// For build systems, the diagnostics can be printed as JSON lines on the
+// standard error (-log=json): the reports, the warnings (skipped files, keys
+// collisions resolved by -on-collision) and the errors, each an object with
+// its level, kind and message, along with the key, path, size or duration
+// concerned:
+//
+//  {"level":"warning","kind":"skip","message":"skipping output file assets/bindata.go","key":"bindata.go","path":"assets/bindata.go"}
+//
+// With -o -, the JSON lines are kept on the standard error.
+//
+// For audit pipelines, the origin of each file (absolute source path, commit
+// checked out in its git repository and modification time) can be written as
+// JSON to a sidecar file (-provenance prov.json), leaving the generated code
+// free of this data.
+//
+// Generation stops at the first input that cannot be read.
+// With -strict, all the inputs are read and the errors (missing paths,
+// permission denied...) are reported together.
 //
 // To see the full list of flags, run:
 //  bindata -h
@@ -40,9 +569,9 @@
 //
 // Running `bindata hello.go` will produce:
 //
-//  package main
+//  // Code generated by bindata. DO NOT EDIT.
 //
-//  // This file is generated. Do not edit directly.
+//  package main
 //
 //  // bindata stores binary files as byte slices indexed by filepaths.
 //  var bindata = map[string][]byte{
@@ -64,165 +593,107 @@
 // Then simply run
 //  go generate
 // and the file jpegs.go will be created.
+//
+// As go generate directives are limited to a line, long lists of flags and
+// paths can be factored out into an argument file given as @file:
+//  //go:generate bindata @assets.args
+// The arguments of the file are separated by spaces or newlines, can be Go
+// quoted strings to hold spaces, and comments start with # and run to the end
+// of the line. Argument files can refer to other argument files, and arguments
+// starting with @ after a -- terminator are paths.
+//
+// The directive can also be added by running, in the directory of the package:
+//  bindata init [-o output.go] [-file source.go] [-n] [paths...]
+// It inserts the directive after the package clause of a source file of the package
+// (-file to choose it), embedding the given paths or the conventional asset
+// directories found (assets, static, public, templates, web, dist).
+// With -n, the directive is only printed.
+//
+// Inspecting generated files
+//
+// The files embedded in generated files can be listed with their sizes,
+// or extracted to the standard output (-x):
+//  bindata inspect [-x key] generated.go [group files...]
+// The files added, removed or changed between two generated files are
+// reported with their sizes by:
+//  bindata diff old.go new.go
+// Generated files of all the layouts can be read, the files embedded with
+// go:embed (-embed-over) being read from their directory next to the
+// generated file.
+//
+// Large embedded files can be hotfixed without shipping a new binary: with
+// -patches, a function (suffix "Patch") applies a binary patch to the contents
+// of a file and returns the new contents, failing if the patch was made from
+// other contents. Much smaller than the files for small changes, the patches
+// copy the unchanged parts of the embedded contents, like bsdiff, and are made
+// from the embedded version of a file and its update by:
+//  bindata patch [-o delta.bin] old new
+//  data, err := bindataPatch("dataset.bin", delta) // downloaded
+//
+// Validating configurations
+//
+// A configuration and the inputs can be checked without generating anything,
+// e.g. as a fast sanity gate in continuous integration:
+//  bindata validate [-c config.json] [-json] [flags] [paths...]
+// It reports unknown fields, unreadable inputs, files of different inputs with
+// the same key, invalid or unused group patterns, files belonging to several
+// groups and exceeded budgets, one per line or as a JSON array of objects with
+// the fields kind, subject and message (-json), and fails if there are any.
+// The flags affecting the keys (-r, -abs, -from-archive, -gitignore) and -budget
+// are those of a normal run.
+//
+// To enforce mechanically that generated files are not edited by hand, they can
+// end with a comment recording their SHA-256 checksum (-checksum), checked by:
+//  bindata verify-file generated.go [group files...]
+// It reports the files edited since they were generated, or without checksum,
+// and fails if there are any.
+//
+// Library use
+//
+// Generations can also be run from Go code, with the arguments of the command
+// line, by the package github.com/simleb/bindata/generate:
+//  err := generate.Generate(ctx, []string{"-o", "assets.go", "static"}, func(p generate.Progress) {
+//  	log.Printf("%d files, added %s (%d bytes)", p.Files, p.Key, p.Size)
+//  })
+// The generation stops when ctx is canceled, while walking directories or
+// reading files. Each generation has its own state, so several can run
+// concurrently in one process, e.g. in a build daemon generating several
+// bundles in parallel.
+//
+// Files which are not on disk can be embedded along with the inputs of the
+// command line, as sources giving their key and their contents: generated
+// contents (generate.BytesSource, or generate.ReaderSource reading them from
+// an io.Reader), files stored elsewhere on disk (generate.FileSource) or
+// downloaded at generation time (generate.URLSource, with an optional pinned
+// SHA-256). Any type with the methods of the generate.Source interface can be
+// embedded as well, except with -per-dir-output:
+//  err := generate.Generate(ctx, []string{"-o", "assets.go", "static"}, nil,
+//  	generate.BytesSource("version.txt", []byte(version)),
+//  	generate.URLSource("schemas/v1.json", "https://example.com/v1/schema.json", ""),
+//  )
+//
+// Server mode
+//
+// To let other tools trigger generations, bindata can run as an HTTP server:
+//  bindata serve [-addr localhost:7878] [-watch 1s] [flags] [paths...]
+// The flags and paths of a normal run can be mixed with the serve flags,
+// up to a -- terminator after which all arguments are left to the runs.
+// A POST request to /generate runs a generation, stopped if the client goes
+// away, and reports its status, also available from /status, and /manifest
+// lists the files of the last generation with their sizes and SHA-256 hashes. With -watch, the inputs are polled at
+// the given interval and the file is regenerated when they change. The files
+// are kept in memory between generations and only read again once modified.
+// The server is not authenticated and writes files: it listens on the loopback
+// interface by default, and should only be exposed to trusted clients.
 package main
 
 import (
-	"bufio"
-	"flag"
-	"fmt"
-	"io"
+	"context"
 	"os"
-	"path/filepath"
-	"text/template"
-)
-
-// tmpl is the template of the generated Go source file.
-var tmpl = template.Must(template.New("bindata").Parse(`package {{.Pkg}}
 
-// This file is generated. Do not edit directly.
-
-// {{.Map}} stores binary files as {{if .AsString}}strings{{else}}byte slices{{end}} indexed by file paths.
-var {{.Map}} = map[string]{{if .AsString}}string{{else}}[]byte{{end}}{{"{"}}{{range $name, $data := .Files}}
-	{{printf "%#v" $name}}: {{printf "%#v" $data}},{{end}}
-}
-`))
-
-// vars contains the variables required by the template.
-var vars struct {
-	Pkg      string
-	Map      string
-	AsString bool
-	Files    map[string]fmt.Formatter
-}
+	"github.com/simleb/bindata/generate"
+)
 
 func main() {
-	if err := run(); err != nil {
-		fmt.Println("bindata:", err)
-		os.Exit(1)
-	}
-}
-
-// run executes the program.
-func run() error {
-	// use GOPACKAGE (set by go generate) as default package name if available
-	pkg := os.Getenv("GOPACKAGE")
-	if pkg == "" {
-		pkg = "main"
-	}
-
-	var out, prefix string
-	fs := flag.NewFlagSet("bindata", flag.ExitOnError)
-	fs.StringVar(&out, "o", "", "output file (default: stdout)")
-	fs.StringVar(&vars.Pkg, "p", pkg, "name of the package")
-	fs.StringVar(&vars.Map, "m", "bindata", "name of the map variable")
-	fs.StringVar(&prefix, "r", "", "root path for map keys")
-	fs.BoolVar(&vars.AsString, "s", false, "save data as strings")
-	if err := fs.Parse(os.Args[1:]); err != nil {
-		return err
-	}
-
-	vars.Files = make(map[string]fmt.Formatter)
-	for _, path := range fs.Args() {
-		if err := AddPath(path, prefix); err != nil {
-			return err
-		}
-	}
-
-	var file *os.File
-	if out != "" {
-		var err error
-		if file, err = os.Create(out); err != nil {
-			return err
-		}
-	} else {
-		file = os.Stdout
-	}
-
-	return tmpl.Execute(file, vars)
-}
-
-// AddPath add files to the slice in vars recursively.
-func AddPath(path, prefix string) error {
-	fi, err := os.Stat(path)
-	if err != nil {
-		return err
-	}
-	if fi.IsDir() {
-		dir, err := os.Open(path)
-		if err != nil {
-			return err
-		}
-		files, err := dir.Readdirnames(0)
-		if err != nil {
-			return err
-		}
-		for _, file := range files {
-			if err := AddPath(filepath.Join(path, file), prefix); err != nil {
-				return err
-			}
-		}
-	} else {
-		file, err := os.Open(path)
-		if err != nil {
-			return err
-		}
-		path, err := filepath.Rel(prefix, path)
-		if err != nil {
-			return err
-		}
-		if vars.AsString {
-			vars.Files[path] = StringFormatter{file}
-		} else {
-			vars.Files[path] = ByteSliceFormatter{file}
-		}
-	}
-	return nil
-}
-
-// A ByteSliceFormatter is a byte slice pretty printing io.Reader.
-type ByteSliceFormatter struct {
-	io.Reader
-}
-
-// Format pretty prints the bytes read from the ByteSliceFormatter.
-func (f ByteSliceFormatter) Format(s fmt.State, c rune) {
-	buf := bufio.NewReader(f)
-
-	const cols = 12 // number of columns in the formatted byte slice.
-
-	fmt.Fprintf(s, "[]byte{")
-	b, err := buf.ReadByte()
-	for i := 0; err == nil; i++ {
-		if i%cols == 0 {
-			fmt.Fprintf(s, "\n\t\t")
-		} else {
-			fmt.Fprintf(s, " ")
-		}
-		fmt.Fprintf(s, "%#02x,", b)
-		b, err = buf.ReadByte()
-	}
-	fmt.Fprintf(s, "\n\t}")
-}
-
-// A StringFormatter is a string pretty printing io.Reader.
-type StringFormatter struct {
-	io.Reader
-}
-
-// Format pretty prints the bytes read from the StringFormatter.
-func (f StringFormatter) Format(s fmt.State, c rune) {
-	buf := bufio.NewReader(f)
-
-	const cols = 16 // number of bytes per line in the formatted string.
-
-	fmt.Fprintf(s, `"`)
-	b, err := buf.ReadByte()
-	for i := 0; err == nil; i++ {
-		if i%cols == 0 {
-			fmt.Fprintf(s, "\" +\n\t\t\"")
-		}
-		fmt.Fprintf(s, "\\x%02x", b)
-		b, err = buf.ReadByte()
-	}
-	fmt.Fprintf(s, `"`)
+	os.Exit(generate.Main(context.Background(), os.Args[1:]))
 }
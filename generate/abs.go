@@ -0,0 +1,42 @@
+package generate
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// escapes reports whether the keys of the files under path would be
+// absolute or escape the root prefix with "..".
+func escapes(path, prefix string) bool {
+	if prefix == "" && filepath.IsAbs(path) {
+		return true
+	}
+	rel, err := filepath.Rel(prefix, path)
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// keyRoot returns the prefix and virtual prefix of the keys of the files under
+// path given the root prefix and the policy for absolute or escaping keys:
+// "reject" fails, "trim" keys the files relative to the parent directory of path
+// and "keep" keeps the absolute or escaping keys.
+func keyRoot(path, prefix, policy string) (string, string, error) {
+	if !escapes(path, prefix) {
+		return prefix, "", nil
+	}
+	switch policy {
+	case "trim":
+		return filepath.Dir(filepath.Clean(path)), "", nil
+	case "keep":
+		if _, err := filepath.Rel(prefix, path); err != nil || prefix == "" {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return "", "", err
+			}
+			root := filepath.VolumeName(abs) + string(filepath.Separator)
+			return root, root, nil
+		}
+		return prefix, "", nil
+	}
+	return "", "", fmt.Errorf("the keys of %s would be absolute or outside of the root %q: use -r, -abs=trim or -abs=keep", path, prefix)
+}
@@ -0,0 +1,56 @@
+package generate
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// TestEscapes tests the detection of absolute or escaping keys.
+func TestEscapes(t *testing.T) {
+	tests := []struct {
+		path, prefix string
+		escapes      bool
+	}{
+		{"static", "", false},
+		{"static/app.js", "static", false},
+		{"../shared", "", true},
+		{"../shared", "..", false},
+		{"/abs/static", "", true},
+		{"/abs/static", "/abs", false},
+		{"/other", "/abs", true},
+	}
+	for _, test := range tests {
+		if got := escapes(filepath.FromSlash(test.path), filepath.FromSlash(test.prefix)); got != test.escapes {
+			t.Errorf("%s (root %q): expected %v, got %v", test.path, test.prefix, test.escapes, got)
+		}
+	}
+}
+
+// TestAbs tests the policies for absolute keys.
+func TestAbs(t *testing.T) {
+	input := filepath.Join(testdata, "play", "bytes")
+	if err := runGenerate([]string{input}); err == nil {
+		t.Error("expected error for absolute keys")
+	}
+
+	tests := map[string]string{
+		"trim": "bytes/11",
+		"keep": filepath.ToSlash(filepath.Join(input, "11")),
+	}
+	for policy, want := range tests {
+		gen := newGenerator(context.Background(), nil)
+		if err := gen.run([]string{"-abs", policy, "-o", filepath.Join(t.TempDir(), "a.go"), input}); err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for key := range gen.assets {
+			keys = append(keys, filepath.ToSlash(key))
+		}
+		sort.Strings(keys)
+		if len(keys) != 3 || keys[0] != want {
+			t.Errorf("%s: unexpected keys %v", policy, keys)
+		}
+	}
+}
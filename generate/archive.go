@@ -0,0 +1,72 @@
+package generate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// IsArchive reports whether the file at path is an archive supported by AddArchive,
+// judging by its extension.
+func IsArchive(path string) bool {
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddArchive adds the files of a zip or tar (optionally gzipped) archive
+// to the assets, using the paths of the entries within the archive as keys.
+func (gen *generator) AddArchive(file string) error {
+	if strings.HasSuffix(strings.ToLower(file), ".zip") {
+		r, err := zip.OpenReader(file)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return gen.AddFS(r, ".")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if !strings.HasSuffix(strings.ToLower(file), ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(h.Name, "/"))
+		if name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("%s: entry %q is outside of the archive", file, h.Name)
+		}
+		if err := gen.addSource(readerSource{name, tr}); err != nil {
+			return err
+		}
+	}
+}
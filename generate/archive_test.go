@@ -0,0 +1,71 @@
+package generate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAddArchive tests embedding the entries of zip and tar archives.
+func TestAddArchive(t *testing.T) {
+	gen := newGenerator(context.Background(), nil)
+	dir := t.TempDir()
+	files := map[string]string{"index.html": "<html>", "js/app.js": "app()"}
+
+	zipFile := filepath.Join(dir, "dist.zip")
+	f, err := os.Create(zipFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(data))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	tgzFile := filepath.Join(dir, "dist.tgz")
+	if f, err = os.Create(tgzFile); err != nil {
+		t.Fatal(err)
+	}
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	tw.WriteHeader(&tar.Header{Name: "./js/", Typeflag: tar.TypeDir, Mode: 0755})
+	for name, data := range files {
+		tw.WriteHeader(&tar.Header{Name: "./" + name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(data))})
+		tw.Write([]byte(data))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	gw.Close()
+	f.Close()
+
+	for _, file := range []string{zipFile, tgzFile} {
+		if !IsArchive(file) {
+			t.Errorf("%s: not recognized as an archive", file)
+		}
+		gen.assets = make(map[string]*Asset)
+		if err := gen.AddArchive(file); err != nil {
+			t.Fatal(err)
+		}
+		if len(gen.assets) != len(files) {
+			t.Errorf("%s: expected %d assets, got %d", file, len(files), len(gen.assets))
+		}
+		for name, data := range files {
+			if a, ok := gen.assets[name]; !ok || string(a.Data) != data {
+				t.Errorf("%s: %s: expected %q, got %+v", file, name, data, a)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package generate
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxArgsDepth is the maximum nesting of argument files, which guards
+// against cycles.
+const maxArgsDepth = 8
+
+// ExpandArgs replaces the arguments of the form @file by the arguments listed
+// in file, so that long go:generate directives can be factored out of the
+// single line go generate allows. The arguments in a file are separated by
+// spaces and newlines, can be Go double-quoted or back-quoted strings to hold
+// spaces, and comments start with # and run to the end of the line. Argument
+// files can refer to other argument files. The arguments following a --
+// terminator are not expanded.
+func ExpandArgs(args []string) ([]string, error) {
+	var expanded []string
+	terminated := false
+	var expand func(args []string, depth int) error
+	expand = func(args []string, depth int) error {
+		for _, arg := range args {
+			file, ok := strings.CutPrefix(arg, "@")
+			if !ok || terminated || file == "" {
+				terminated = terminated || arg == "--"
+				expanded = append(expanded, arg)
+				continue
+			}
+			if depth == maxArgsDepth {
+				return fmt.Errorf("@%s: too many nested argument files", file)
+			}
+			b, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			fileArgs, err := ParseArgs(string(b))
+			if err != nil {
+				return fmt.Errorf("%s:%v", file, err)
+			}
+			if err := expand(fileArgs, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := expand(args, 0); err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+// ParseArgs splits the contents of an argument file into arguments. Errors
+// are prefixed with the line number.
+func ParseArgs(s string) ([]string, error) {
+	var args []string
+	for n, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		for line != "" {
+			var arg string
+			switch line[0] {
+			case '#':
+				line = ""
+				continue
+			case '"', '`':
+				quoted, err := strconv.QuotedPrefix(line)
+				if err != nil {
+					return nil, fmt.Errorf("%d: invalid quoted string %s", n+1, line)
+				}
+				arg, _ = strconv.Unquote(quoted)
+				line = line[len(quoted):]
+			default:
+				i := strings.IndexFunc(line, unicode.IsSpace)
+				if i < 0 {
+					i = len(line)
+				}
+				arg, line = line[:i], line[i:]
+			}
+			args = append(args, arg)
+			line = strings.TrimLeftFunc(line, unicode.IsSpace)
+		}
+	}
+	return args, nil
+}
+
+// parseFlags parses args with fs, which must continue on error so that the
+// error is returned to the caller instead of exiting. The usage is only
+// printed when asked for with -h, the other errors being reported once by
+// the caller.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard)
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		fs.SetOutput(os.Stderr)
+		fs.Usage()
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestParseArgs tests the parsing of argument files.
+func TestParseArgs(t *testing.T) {
+	const file = `# assets of the site
+-o assets.go   # output
+-compress gzip
+"dir with spaces" ` + "`raw\\path`" + `
+	static/#not-a-comment
+`
+	want := []string{"-o", "assets.go", "-compress", "gzip", "dir with spaces", `raw\path`, "static/#not-a-comment"}
+	args, err := ParseArgs(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(args, want) {
+		t.Errorf("expected %q, got %q", want, args)
+	}
+	if _, err := ParseArgs("-o x\n\"unterminated"); err == nil || err.Error() != `2: invalid quoted string "unterminated` {
+		t.Errorf("expected error on line 2, got %v", err)
+	}
+}
+
+// TestExpandArgs tests the generation with arguments read from files.
+func TestExpandArgs(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.go")
+	inner, outer := filepath.Join(dir, "inner.txt"), filepath.Join(dir, "outer.txt")
+	if err := os.WriteFile(inner, []byte("-r "+strconv.Quote(testdata)+"\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(outer, []byte("# nested\n@"+inner+"\n-o "+strconv.Quote(out)+"\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	args, err := ExpandArgs([]string{"-m", "assets", "@" + outer, "--", "@literal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"-m", "assets", "-r", testdata, "-o", out, "--", "@literal"}; !slices.Equal(args, want) {
+		t.Errorf("expected %q, got %q", want, args)
+	}
+	if err := runGenerate([]string{"@" + outer, filepath.Join(testdata, "play", "bytes")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Error(err)
+	}
+
+	// cycles are detected
+	if err := os.WriteFile(inner, []byte("@"+outer), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExpandArgs([]string{"@" + outer}); err == nil {
+		t.Error("expected error for cycle")
+	}
+	if _, err := ExpandArgs([]string{"@" + filepath.Join(dir, "missing")}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+// TestParseFlags tests that the subcommands return their flag errors
+// instead of exiting.
+func TestParseFlags(t *testing.T) {
+	for name, run := range map[string]func([]string) error{
+		"budget-check": runBudgetCheck,
+		"verify-file":  runVerifyFile,
+		"diff":         runDiff,
+		"init":         runInit,
+		"inspect":      runInspect,
+		"patch":        runPatch,
+		"pin":          runPin,
+		"sign":         runSign,
+		"validate":     runValidate,
+	} {
+		if err := run([]string{"-unknown"}); err == nil || !strings.Contains(err.Error(), "-unknown") {
+			t.Errorf("%s: expected unknown flag error, got %v", name, err)
+		}
+	}
+}
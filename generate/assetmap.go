@@ -0,0 +1,90 @@
+package generate
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"unicode/utf8"
+)
+
+// An AssetMap maps the keys of the files of a generation to the names they
+// are embedded under: their content-addressed names with -hash-names, the
+// keys themselves otherwise. Written as JSON (-json-manifest) or TypeScript
+// declarations (-ts-manifest), it lets frontend tooling reference the same
+// canonical assets as the Go code.
+type AssetMap map[string]string
+
+// NewAssetMap returns the asset map of the given assets, named after their
+// content-addressed keys in hashed if they have one.
+func NewAssetMap(assets map[string]*Asset, hashed map[string]string) AssetMap {
+	m := make(AssetMap, len(assets))
+	for key := range assets {
+		name := filepath.ToSlash(key)
+		if h, ok := hashed[key]; ok {
+			name = h
+		}
+		m[filepath.ToSlash(key)] = name
+	}
+	return m
+}
+
+// check returns an error if a key is not valid UTF-8, which neither JSON nor
+// TypeScript can represent.
+func (m AssetMap) check() error {
+	for key := range m {
+		if !utf8.ValidString(key) {
+			return fmt.Errorf("cannot represent %q in a manifest: invalid UTF-8", key)
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the asset map as an indented JSON object sorted by key.
+func (m AssetMap) WriteJSON(w io.Writer) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(m)
+}
+
+// WriteTS writes the asset map as TypeScript declarations: a union type of
+// the keys (AssetPath) and the default export of a constant named name
+// mapping them to their names, e.g. to type the JSON manifest imported as a
+// module. The declarations start with the comment marking generated files,
+// if any.
+func (m AssetMap) WriteTS(w io.Writer, generated, name string) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if generated != "" {
+		fmt.Fprintf(bw, "%s\n\n", generated)
+	}
+	keys := slices.Sorted(maps.Keys(m))
+	bw.WriteString("/** Paths of the embedded files. */\nexport type AssetPath =")
+	if len(keys) == 0 {
+		bw.WriteString(" never")
+	}
+	for _, key := range keys {
+		fmt.Fprintf(bw, "\n\t| %s", tsString(key))
+	}
+	fmt.Fprintf(bw, ";\n\n/** Names of the embedded files by path. */\ndeclare const %s: {\n", name)
+	for _, key := range keys {
+		fmt.Fprintf(bw, "\treadonly %s: %s;\n", tsString(key), tsString(m[key]))
+	}
+	fmt.Fprintf(bw, "};\nexport default %s;\n", name)
+	return bw.Flush()
+}
+
+// tsString returns s as a TypeScript string literal. JSON strings are valid
+// TypeScript string literals.
+func tsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,58 @@
+package generate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAssetMap tests the manifests of the files written for frontend tooling.
+func TestAssetMap(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile, tsFile := filepath.Join(dir, "assets.json"), filepath.Join(dir, "assets.d.ts")
+	args := []string{"-hash-names", "-json-manifest", jsonFile, "-ts-manifest", tsFile, "-o", filepath.Join(dir, "out.go"),
+		"-r", testdata, filepath.Join(testdata, "play", "bytes", "11"), filepath.Join(testdata, "empty")}
+	if err := runGenerate(args); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m AssetMap
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || m["play/bytes/11"] != "play/bytes/11.eab36655" || m["empty"] != hashedName("empty", cacheHashes["sha256"](&Asset{})) {
+		t.Errorf("unexpected JSON manifest %v", m)
+	}
+
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+/** Paths of the embedded files. */
+export type AssetPath =
+	| "empty"
+	| "play/bytes/11";
+
+/** Names of the embedded files by path. */
+declare const bindata: {
+	readonly "empty": "empty.e3b0c442";
+	readonly "play/bytes/11": "play/bytes/11.eab36655";
+};
+export default bindata;
+`
+	if b, err := os.ReadFile(tsFile); err != nil || string(b) != ref {
+		t.Errorf("unexpected TypeScript manifest (%v):\n%s", err, b)
+	}
+
+	var sb strings.Builder
+	if err := (AssetMap{"a\xff": "a\xff"}).WriteTS(&sb, "", "m"); err == nil {
+		t.Error("expected error for invalid UTF-8")
+	}
+	if err := (AssetMap{}).WriteTS(&sb, "", "m"); err != nil || !strings.Contains(sb.String(), "AssetPath = never;") {
+		t.Errorf("unexpected empty manifest (%v):\n%s", err, sb.String())
+	}
+}
@@ -0,0 +1,190 @@
+package generate
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A SizeManifest records the sizes of the files of a generation. Committed as a
+// baseline, it turns asset bloat into a reviewable failure (budget-check).
+type SizeManifest struct {
+	Total Size            `json:"total"`
+	Files map[string]Size `json:"files"`
+}
+
+// NewSizeManifest returns the manifest of the given assets.
+func NewSizeManifest(assets map[string]*Asset) *SizeManifest {
+	m := &SizeManifest{Files: make(map[string]Size, len(assets))}
+	for key, a := range assets {
+		m.Files[filepath.ToSlash(key)] = Size(len(a.Data))
+		m.Total += Size(len(a.Data))
+	}
+	return m
+}
+
+// LoadSizeManifest reads a manifest from a JSON file.
+func LoadSizeManifest(file string) (*SizeManifest, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var m SizeManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return &m, nil
+}
+
+// WriteJSON writes the manifest as indented JSON. It fails if a key is not
+// valid UTF-8, which JSON cannot represent.
+func (m *SizeManifest) WriteJSON(w io.Writer) error {
+	for key := range m.Files {
+		if !utf8.ValidString(key) {
+			return fmt.Errorf("cannot represent %q in JSON: invalid UTF-8", key)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(m)
+}
+
+// A Growth is the maximum growth of a size, relative ("10%") or absolute
+// ("100KB"). The zero value sets no limit.
+type Growth struct {
+	Limit    float64 // percentage if relative, number of bytes otherwise
+	Relative bool
+	set      bool
+}
+
+// String returns the growth as it would appear on the command line.
+func (g *Growth) String() string {
+	switch {
+	case !g.set:
+		return ""
+	case g.Relative:
+		return strconv.FormatFloat(g.Limit, 'f', -1, 64) + "%"
+	}
+	return Size(g.Limit).String()
+}
+
+// Set parses a growth from a command line flag.
+func (g *Growth) Set(value string) error {
+	if num, ok := strings.CutSuffix(strings.TrimSpace(value), "%"); ok {
+		f, err := strconv.ParseFloat(num, 64)
+		if err != nil || f < 0 {
+			return fmt.Errorf("invalid growth %q", value)
+		}
+		*g = Growth{Limit: f, Relative: true, set: true}
+		return nil
+	}
+	size, err := ParseSize(value)
+	if err != nil {
+		return err
+	}
+	*g = Growth{Limit: float64(size), set: true}
+	return nil
+}
+
+// Exceeded reports whether growing from old to new exceeds the growth.
+// Any growth of an empty or new file exceeds a relative limit.
+func (g Growth) Exceeded(old, new Size) bool {
+	if !g.set || new <= old {
+		return false
+	}
+	if g.Relative {
+		return old == 0 || float64(new-old) > float64(old)*g.Limit/100
+	}
+	return float64(new-old) > g.Limit
+}
+
+// A Regression is a file, or the total, which grew beyond its limit.
+type Regression struct {
+	Key      string // key of the file, empty for the total
+	Old, New Size
+}
+
+// String describes the regression, e.g. "app.js: 100KB -> 150KB (+50%)".
+func (r Regression) String() string {
+	key := r.Key
+	if key == "" {
+		key = "total"
+	}
+	if r.Old == 0 {
+		return fmt.Sprintf("%s: new file of %v", key, r.New)
+	}
+	return fmt.Sprintf("%s: %v -> %v (+%.0f%%)", key, r.Old, r.New, float64(r.New-r.Old)*100/float64(r.Old))
+}
+
+// CheckGrowth returns the regressions of cur compared to the baseline base:
+// the total first, if it grew beyond total, then the files which grew beyond
+// file, new files included, by key.
+func CheckGrowth(base, cur *SizeManifest, total, file Growth) []Regression {
+	var regressions []Regression
+	if total.Exceeded(base.Total, cur.Total) {
+		regressions = append(regressions, Regression{"", base.Total, cur.Total})
+	}
+	for _, key := range slices.Sorted(maps.Keys(cur.Files)) {
+		if old := base.Files[key]; file.Exceeded(old, cur.Files[key]) {
+			regressions = append(regressions, Regression{key, old, cur.Files[key]})
+		}
+	}
+	return regressions
+}
+
+// runBudgetCheck runs the budget-check subcommand.
+func runBudgetCheck(args []string) error {
+	var baseline, prefix, abs string
+	var total, file Growth
+	var update, fromArchive, gitignore bool
+	fs := flag.NewFlagSet("bindata budget-check", flag.ContinueOnError)
+	fs.StringVar(&baseline, "baseline", "", "manifest of the sizes of the files to compare to (JSON)")
+	fs.Var(&total, "max-growth", "maximum growth of the total size, relative (e.g. 5%) or absolute (e.g. 100KB)")
+	fs.Var(&file, "max-file-growth", "maximum growth of the size of each file, relative (e.g. 10%) or absolute (e.g. 50KB)")
+	fs.BoolVar(&update, "update", false, "write the current sizes to the baseline instead of checking them")
+	fs.StringVar(&prefix, "r", "", "root path for map keys")
+	fs.StringVar(&abs, "abs", "reject", "policy for keys absolute or outside of the root: reject, trim or keep")
+	fs.BoolVar(&fromArchive, "from-archive", false, "treat zip and tar archives as directory trees")
+	fs.BoolVar(&gitignore, "gitignore", false, "honour .gitignore files in directories")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if baseline == "" {
+		return fmt.Errorf("budget-check: missing -baseline")
+	}
+	gen := newGenerator(context.Background(), nil)
+	if gitignore {
+		gen.ignoreFiles = append(gen.ignoreFiles, ".gitignore")
+	}
+
+	for _, path := range fs.Args() {
+		if err := gen.addInput(path, prefix, abs, fromArchive); err != nil {
+			return err
+		}
+	}
+	cur := NewSizeManifest(gen.assets)
+	if update {
+		return WriteFile(baseline, cur.WriteJSON)
+	}
+	base, err := LoadSizeManifest(baseline)
+	if err != nil {
+		return err
+	}
+	regressions := CheckGrowth(base, cur, total, file)
+	for _, r := range regressions {
+		fmt.Println(r)
+	}
+	if len(regressions) > 0 {
+		return fmt.Errorf("budget-check: %d size regression(s) against %s", len(regressions), baseline)
+	}
+	return nil
+}
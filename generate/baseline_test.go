@@ -0,0 +1,66 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCheckGrowth tests the detection of size regressions against a baseline.
+func TestCheckGrowth(t *testing.T) {
+	base := &SizeManifest{Total: 300, Files: map[string]Size{"a": 100, "b": 100, "c": 100}}
+	cur := &SizeManifest{Total: 460, Files: map[string]Size{"a": 105, "b": 200, "c": 50, "d": 105}}
+	var total, file Growth
+	if regressions := CheckGrowth(base, cur, total, file); regressions != nil {
+		t.Errorf("unexpected regressions without limits: %v", regressions)
+	}
+	for _, v := range []string{"50%", "10%"} {
+		g := &total
+		if v == "10%" {
+			g = &file
+		}
+		if err := g.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []string
+	for _, r := range CheckGrowth(base, cur, total, file) {
+		got = append(got, r.String())
+	}
+	want := "total: 300B -> 460B (+53%), b: 100B -> 200B (+100%), d: new file of 105B"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("expected %s, got %s", want, strings.Join(got, ", "))
+	}
+	if err := file.Set("200B"); err != nil || file.String() != "200B" {
+		t.Fatal(err)
+	}
+	if regressions := CheckGrowth(base, cur, Growth{}, file); regressions != nil {
+		t.Errorf("unexpected regressions with absolute limit: %v", regressions)
+	}
+	if err := file.Set("x%"); err == nil {
+		t.Error("expected error for invalid growth")
+	}
+}
+
+// TestBudgetCheck tests the budget-check subcommand with a baseline written by -update.
+func TestBudgetCheck(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("small"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	baseline := filepath.Join(t.TempDir(), "sizes.json")
+	if err := runBudgetCheck([]string{"-baseline", baseline, "-update", "-r", dir, dir}); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-baseline", baseline, "-max-file-growth", "10%", "-r", dir, dir}
+	if err := runBudgetCheck(args); err != nil {
+		t.Errorf("unexpected error for unchanged files: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte(strings.Repeat("big", 10)), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runBudgetCheck(args); err == nil {
+		t.Error("expected error for file grown beyond its limit")
+	}
+}
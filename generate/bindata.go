@@ -0,0 +1,2739 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// tmplText is the template of the generated Go source file, parsed by each
+// generator.
+const tmplText = `{{.Header}}{{with .Generated}}{{.}}
+
+{{end}}{{with .Doc}}// Package {{$.Pkg}} embeds the following files:
+//
+{{range .}}//	{{.}}
+{{end}}{{end}}package {{.Pkg}}
+{{if .Imports}}
+import ({{range .Imports}}
+	{{printf "%q" .}}{{end}}
+)
+{{end}}{{if .Blob}}
+// {{.Map}}Blob holds the contents of all the files, concatenated.
+const {{.Map}}Blob = {{printf "%#v" .Blob}}
+
+// {{.Var}} stores the offsets and sizes of the files in {{.Map}}Blob sorted by {{if .Salt}}hashes of {{end}}file paths.
+// Use {{.Map}}Lookup to find a file.
+var {{.Var}} = []struct {
+	Name         string
+	Offset, Size int
+}{{"{"}}{{range $name, $data := .Files}}{{with index $.Comments $name}}
+	// {{.}}{{end}}
+	{{"{"}}{{printf "%#v" $name}}{{with index $.Offsets $name}}, {{index . 0}}, {{index . 1}}{{end}}},{{end}}{{if .Files}}
+{{end}}}
+{{else if .PerFile}}{{range $name, $data := .Files}}{{$id := index $.Idents $name}}
+// {{$.Unexported}}_{{$id}} holds the contents of {{printf "%#v" $name}}.{{with index $.Comments $name}}
+// {{.}}{{end}}
+var {{$.Unexported}}_{{$id}} = {{printf "%#v" $data}}
+
+// {{$.Map}}{{$id}} returns the contents of {{printf "%#v" $name}}.
+func {{$.Map}}{{$id}}() {{if $.AsString}}string{{else}}[]byte{{end}} {
+	return {{$.Unexported}}_{{$id}}
+}
+{{end}}{{else}}
+// {{.Var}} stores binary files as {{if .AsString}}strings{{else}}byte slices{{end}} {{if .Slice}}sorted by{{else}}indexed by{{end}} {{if .Salt}}hashes of {{end}}file paths.{{if .ReadOnly}}
+// Use {{.Map}}Asset or {{.Map}}AssetUnsafe to access the data.{{else if .BytesViaString}}
+// Use {{.Map}}Bytes to access the data as byte slices.{{end}}
+{{if .Slice}}// Use {{.Map}}Lookup to find a file.
+var {{.Var}} = []struct {
+	Name string
+	Data {{if .AsString}}string{{else}}[]byte{{end}}
+}{{"{"}}{{range $name, $data := .Files}}{{with index $.Comments $name}}
+	// {{.}}{{end}}
+	{{"{"}}{{printf "%#v" $name}}, {{printf "%#v" $data}}},{{end}}{{if .Files}}
+{{end}}}
+{{end}}{{end}}{{if .Slice}}
+// {{.Map}}Lookup returns the contents of the named file by binary search,
+// or false if there is no such file.
+func {{.Map}}Lookup(name string) ({{if .AsString}}string{{else}}[]byte{{end}}, bool) {
+	i, j := 0, len({{.Var}})
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if {{.Var}}[h].Name < name {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	if i < len({{.Var}}) && {{.Var}}[i].Name == name {{"{"}}{{if .Hook}}
+		if {{.Map}}OnAccess != nil {
+			{{.Map}}OnAccess(name)
+		}{{end}}
+		return {{if .Blob}}{{.Map}}Blob[{{.Var}}[i].Offset : {{.Var}}[i].Offset+{{.Var}}[i].Size]{{else}}{{.Var}}[i].Data{{end}}, true
+	}
+	return {{if .AsString}}""{{else}}nil{{end}}, false
+}
+{{else if not .PerFile}}var {{.Var}} = map[string]{{if .AsString}}string{{else}}[]byte{{end}}{{"{"}}{{range aligned .Files .Comments}}{{with index $.Comments .Name}}
+	// {{.}}{{end}}
+	{{.Key}}{{printf "%#v" .Value}},{{end}}{{if ne (len .Files) (len .Embedded)}}
+{{end}}}
+{{end}}{{if .Embedded}}
+// {{.Unexported}}EmbedFS holds the files of {{.Var}} larger than {{.EmbedOver}}, embedded from
+// the directory {{.EmbedDir}} and added to {{.Var}} on initialization.
+//
+//go:embed {{.EmbedDir}}
+var {{.Unexported}}EmbedFS embed.FS
+
+func init() {
+	for name, file := range map[string]string{{"{"}}{{range aligned .Embedded .Comments}}{{with index $.Comments .Name}}
+		// {{.}}{{end}}
+		{{.Key}}{{printf "%#v" .Value}},{{end}}
+	} {
+		data, err := {{.Unexported}}EmbedFS.ReadFile({{printf "%#v" (print .EmbedDir "/")}} + file)
+		if err != nil {
+			panic(err)
+		}
+		{{.Var}}[name] = {{if .AsString}}string(data){{else}}data{{end}}
+	}
+}
+{{end}}{{if .Aliases}}
+// {{.Map}}Aliases maps aliases of files of {{.Var}}, e.g. legacy paths, to their keys.
+// They are added to {{.Var}} on initialization, sharing the contents of the files.
+var {{.Map}}Aliases = map[string]string{{"{"}}{{range aligned .Aliases nil}}
+	{{.Key}}{{printf "%#v" .Value}},{{end}}
+}
+
+func init() {
+	for alias, key := range {{.Map}}Aliases {
+		{{.Var}}[alias] = {{.Var}}[key]{{if .Precompressed}}
+		if gz, ok := {{.Map}}Gzip[key]; ok {
+			{{.Map}}Gzip[alias] = gz
+		}{{end}}
+	}
+}
+{{end}}{{if .Errors}}
+// {{.Map}}ErrAssetNotFound is the error returned by the accessors for a missing
+// file. It matches os.ErrNotExist with errors.Is.
+type {{.Map}}ErrAssetNotFound struct {
+	Name string // name of the file
+}
+
+func (e {{.Map}}ErrAssetNotFound) Error() string {
+	return "{{.Map}}: file not found: " + e.Name
+}
+
+// Is reports whether target is os.ErrNotExist.
+func (e {{.Map}}ErrAssetNotFound) Is(target error) bool {
+	return target == os.ErrNotExist
+}
+
+// {{.Map}}ErrDecode is the error returned by the accessors for a file which
+// cannot be decompressed or decoded. It wraps the error of the decoder.
+type {{.Map}}ErrDecode struct {
+	Name string // name of the file
+	Err  error  // error of the decoder
+}
+
+func (e {{.Map}}ErrDecode) Error() string {
+	return "{{.Map}}: decode " + e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error of the decoder.
+func (e {{.Map}}ErrDecode) Unwrap() error {
+	return e.Err
+}
+{{end}}{{if .Hook}}
+// {{.Map}}OnAccess, if not nil, is called with the name of each file accessed
+// through the generated functions, e.g. to record the files used.
+var {{.Map}}OnAccess func(name string)
+{{end}}{{if .Salt}}
+// {{.Unexported}}Salt is mixed into the hashes of the file names.
+const {{.Unexported}}Salt = {{printf "%#v" .Salt}}
+
+// {{.Map}}Key returns the key of the named file in {{.Var}}: a salted hash of the name,
+// so that the names of the files do not appear in binaries.
+func {{.Map}}Key(name string) string {
+	sum := sha256.Sum256([]byte({{.Unexported}}Salt + name))
+	return hex.EncodeToString(sum[:{{.SaltLen}}])
+}
+
+// {{.Map}}Get returns the contents of the named file, or false if there is no such file.
+func {{.Map}}Get(name string) ({{if .AsString}}string{{else}}[]byte{{end}}, bool) {
+	{{if .Slice}}data, ok := {{.Map}}Lookup({{.Map}}Key(name)){{else}}data, ok := {{.Var}}[{{.Map}}Key(name)]{{end}}{{if and .Hook (not .Slice)}}
+	if ok && {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}
+	return data, ok
+}
+{{end}}{{if .ReadOnly}}{{if not .AssetError}}
+// {{.Map}}Asset returns a copy of the contents of the named file,
+// or false if there is no such file.
+func {{.Map}}Asset(name string) ([]byte, bool) {
+	s, ok := {{.Var}}[name]
+	if !ok {
+		return nil, false
+	}{{if .Hook}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}
+	return []byte(s), true
+}
+{{end}}
+// {{.Map}}AssetUnsafe returns the contents of the named file without copying them,
+// or false if there is no such file. The returned slice must not be modified.
+func {{.Map}}AssetUnsafe(name string) ([]byte, bool) {
+	s, ok := {{.Var}}[name]
+	if !ok {
+		return nil, false
+	}{{if .Hook}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}
+	if len(s) == 0 {
+		return []byte{}, true // unsafe.StringData is unspecified for empty strings
+	}
+	return unsafe.Slice(unsafe.StringData(s), len(s)), true
+}
+{{end}}{{if .BytesViaString}}
+// {{.Map}}Bytes returns the contents of the named file converted to a byte slice,
+// or nil if there is no such file.
+func {{.Map}}Bytes(name string) []byte {
+	s, ok := {{.Var}}[name]
+	if !ok {
+		return nil
+	}{{if .Hook}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}
+	return []byte(s)
+}
+{{end}}{{if .Compress}}
+// {{.Map}}Codecs maps the files of {{.Var}} stored compressed to their codec.
+var {{.Map}}Codecs = map[string]string{{"{"}}{{range aligned .Codecs nil}}
+	{{.Key}}{{printf "%#v" .Value}},{{end}}{{if .Codecs}}
+{{end}}}
+
+// {{.Map}}Read returns a copy of the contents of the named file, decompressed
+// if needed.
+func {{.Map}}Read(name string) ([]byte, error) {
+	data, ok := {{.Var}}[name]
+	if !ok {
+		return nil, {{.Map}}ErrAssetNotFound{Name: name}
+	}{{if .Hook}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}{{if .Preload}}
+	if b, ok := {{.Unexported}}Preloaded.Load(name); ok {
+		return append([]byte{}, b.([]byte)...), nil
+	}{{end}}{{if .SpillOver}}
+	if sum, ok := {{.Unexported}}Spilled[name]; ok {
+		path, err := {{.Unexported}}Spill(name, sum)
+		if err != nil {
+			return nil, err
+		}
+		return os.ReadFile(path)
+	}{{end}}
+	b, err := {{.Unexported}}Decompress(name, data)
+	if err != nil {
+		return nil, {{.Map}}ErrDecode{Name: name, Err: err}
+	}
+	return b, nil
+}
+
+// {{.Unexported}}Decompress returns a copy of the contents of the named file
+// stored as data, decompressed if needed.
+func {{.Unexported}}Decompress(name string, data {{if .AsString}}string{{else}}[]byte{{end}}) ([]byte, error) {
+	switch {{.Map}}Codecs[name] {{"{"}}{{if eq .Compress "gzip" "auto"}}
+	case "gzip":
+		r, err := gzip.NewReader({{if .AsString}}strings{{else}}bytes{{end}}.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return {{if lt .Go 16}}ioutil{{else}}io{{end}}.ReadAll(r){{end}}{{if eq .Compress "flate" "auto"}}
+	case "flate":
+		r := flate.NewReader({{if .AsString}}strings{{else}}bytes{{end}}.NewReader(data))
+		defer r.Close()
+		return {{if lt .Go 16}}ioutil{{else}}io{{end}}.ReadAll(r){{end}}
+	}
+	return {{if .AsString}}[]byte(data){{else}}append([]byte{}, data...){{end}}, nil
+}
+{{if .SpillOver}}
+// {{.Map}}SpillDir is the directory the compressed files larger than {{.SpillOver}} are
+// decompressed to on first access, and read from thereafter, so that their
+// contents are not held in memory. It defaults to a directory of the user
+// cache directory. The files are named after their SHA-256 checksums, so that
+// programs and versions can share it.
+var {{.Map}}SpillDir = {{.Unexported}}DefaultSpillDir()
+
+// {{.Unexported}}DefaultSpillDir returns the default {{.Map}}SpillDir, in the
+// temporary directory if there is no user cache directory.
+func {{.Unexported}}DefaultSpillDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "bindata")
+}
+
+// {{.Unexported}}Spilled maps the files spilled to {{.Map}}SpillDir to the
+// SHA-256 checksums of their contents.
+var {{.Unexported}}Spilled = map[string]string{{"{"}}{{range aligned .Spilled nil}}
+	{{.Key}}{{printf "%#v" .Value}},{{end}}{{if .Spilled}}
+{{end}}}
+
+// {{.Unexported}}SpillChecked records the paths of the spilled files written or
+// checked by this process.
+var {{.Unexported}}SpillChecked sync.Map
+
+// {{.Map}}Open opens the named file for reading. The files spilled to disk are
+// read from {{.Map}}SpillDir, the others from memory.
+func {{.Map}}Open(name string) (io.ReadCloser, error) {
+	sum, ok := {{.Unexported}}Spilled[name]
+	if !ok {
+		data, err := {{.Map}}Read(name)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}{{if .Hook}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}
+	path, err := {{.Unexported}}Spill(name, sum)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// {{.Unexported}}Spill returns the path of the named spilled file, named after the
+// checksum sum of its contents, decompressing it to {{.Map}}SpillDir unless it
+// is already there, e.g. written by a previous run.
+func {{.Unexported}}Spill(name, sum string) (string, error) {
+	path := filepath.Join({{.Map}}SpillDir, sum)
+	if _, ok := {{.Unexported}}SpillChecked.Load(path); ok {
+		return path, nil
+	}
+	if {{.Unexported}}SpillValid(path, sum) {
+		{{.Unexported}}SpillChecked.Store(path, true)
+		return path, nil
+	}
+	if err := os.MkdirAll({{.Map}}SpillDir, 0755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp({{.Map}}SpillDir, sum+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name()) // if not renamed
+	err = {{.Unexported}}Inflate(name, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	// the rename is atomic: concurrent accesses see the whole file or none
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+	{{.Unexported}}SpillChecked.Store(path, true)
+	return path, nil
+}
+
+// {{.Unexported}}SpillValid reports whether the file at path exists and has
+// the SHA-256 checksum sum.
+func {{.Unexported}}SpillValid(path, sum string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return false
+	}
+	return hex.EncodeToString(h.Sum(nil)) == sum
+}
+
+// {{.Unexported}}Inflate writes the decompressed contents of the named file to w,
+// without holding them in memory.
+func {{.Unexported}}Inflate(name string, w io.Writer) error {
+	r := {{if .AsString}}strings{{else}}bytes{{end}}.NewReader({{.Var}}[name])
+	switch {{.Map}}Codecs[name] {{"{"}}{{if eq .Compress "gzip" "auto"}}
+	case "gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		_, err = io.Copy(w, zr)
+		return err{{end}}{{if eq .Compress "flate" "auto"}}
+	case "flate":
+		zr := flate.NewReader(r)
+		defer zr.Close()
+		_, err := io.Copy(w, zr)
+		return err{{end}}
+	}
+	_, err := r.WriteTo(w)
+	return err
+}
+{{end}}{{if .Preload}}
+// {{.Unexported}}Preloaded caches the files decompressed in advance, indexed by name.
+var {{.Unexported}}Preloaded sync.Map
+
+// {{.Map}}PreloadConcurrency is the number of files decompressed at once by
+// {{.Map}}PreloadAll.
+var {{.Map}}PreloadConcurrency = 4
+
+// {{.Map}}Preload decompresses the named files concurrently in the background,
+// e.g. the hot files at startup, so that {{.Map}}Read returns them without
+// decompressing them. The missing and uncompressed files are ignored.
+func {{.Map}}Preload(names ...string) {
+	for _, name := range names {
+		go {{.Unexported}}PreloadFile(name)
+	}
+}
+
+// {{.Map}}PreloadAll decompresses all the compressed files concurrently and
+// waits for them, or until ctx is done, in which case it returns the error
+// of ctx. Run it in a goroutine to preload the files in the background.
+func {{.Map}}PreloadAll(ctx context.Context) error {
+	n := {{.Map}}PreloadConcurrency
+	if n < 1 {
+		n = 1
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, n)
+	defer wg.Wait()
+	for name := range {{.Map}}Codecs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			{{.Unexported}}PreloadFile(name)
+			<-sem
+		}(name)
+	}
+	return nil
+}
+
+// {{.Unexported}}PreloadFile decompresses the named file into {{.Unexported}}Preloaded.
+func {{.Unexported}}PreloadFile(name string) {
+	data, ok := {{.Var}}[name]
+	if _, compressed := {{.Map}}Codecs[name]; !ok || !compressed {
+		return
+	}
+	if _, done := {{.Unexported}}Preloaded.Load(name); done {
+		return
+	}{{if .SpillOver}}
+	if sum, ok := {{.Unexported}}Spilled[name]; ok {
+		{{.Unexported}}Spill(name, sum) // decompressed to disk, not memory
+		return
+	}{{end}}
+	if b, err := {{.Unexported}}Decompress(name, data); err == nil {
+		{{.Unexported}}Preloaded.Store(name, b)
+	}
+}
+{{end}}{{end}}{{if .Writer}}
+// {{.Map}}WriteTo writes the contents of the named file to w, decompressed
+// on the fly if needed, without copying them in memory.
+// It returns the number of bytes written.
+func {{.Map}}WriteTo(name string, w io.Writer) (int64, error) {
+	{{if .Slice}}data, ok := {{.Map}}Lookup(name){{else}}data, ok := {{.Var}}[name]{{end}}
+	if !ok {
+		return 0, {{.Map}}ErrAssetNotFound{Name: name}
+	}{{if and .Hook (not .Slice)}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}
+	r := {{if .AsString}}strings{{else}}bytes{{end}}.NewReader(data){{if .Compress}}
+	switch {{.Map}}Codecs[name] {{"{"}}{{if eq .Compress "gzip" "auto"}}
+	case "gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return 0, err
+		}
+		defer zr.Close()
+		return io.Copy(w, zr){{end}}{{if eq .Compress "flate" "auto"}}
+	case "flate":
+		zr := flate.NewReader(r)
+		defer zr.Close()
+		return io.Copy(w, zr){{end}}
+	}{{end}}
+	return r.WriteTo(w)
+}
+{{end}}{{if .Patch}}
+// {{.Map}}Patch applies a binary patch made by "bindata patch" from the contents
+// of the named file, e.g. downloaded to hotfix a large file without shipping a
+// new binary, and returns the patched contents. It fails if there is no such
+// file, or if the patch was made from other contents or is corrupted.
+func {{.Map}}Patch(name string, patch []byte) ([]byte, error) {
+	{{if .Compress}}old, err := {{.Map}}Read(name)
+	if err != nil {
+		return nil, err
+	}{{else}}{{if .Slice}}old, ok := {{.Map}}Lookup(name){{else}}old, ok := {{.Var}}[name]{{end}}
+	if !ok {
+		return nil, {{.Map}}ErrAssetNotFound{Name: name}
+	}{{if and .Hook (not .Slice)}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}{{end}}
+	if len(patch) < 4+2*sha256.Size || string(patch[:4]) != "BDP1" {
+		return nil, errors.New("{{.Map}}: not a patch of " + name)
+	}
+	if sum := sha256.Sum256([]byte(old)); string(sum[:]) != string(patch[4:36]) {
+		return nil, errors.New("{{.Map}}: patch made from other contents of " + name)
+	}
+	corrupted := errors.New("{{.Map}}: corrupted patch of " + name)
+	want, p := patch[36:68], patch[68:]
+	size, n := binary.Uvarint(p)
+	if n <= 0 {
+		return nil, corrupted
+	}
+	var data []byte
+	for p = p[n:]; len(p) > 0; {
+		code := p[0]
+		length, n := binary.Uvarint(p[1:])
+		if n <= 0 {
+			return nil, corrupted
+		}
+		p = p[1+n:]
+		switch {
+		case code == 0 && length <= uint64(len(p)): // bytes added
+			data = append(data, p[:length]...)
+			p = p[length:]
+		case code == 1: // bytes copied from the embedded contents
+			offset, n := binary.Uvarint(p)
+			if n <= 0 || offset > uint64(len(old)) || length > uint64(len(old))-offset {
+				return nil, corrupted
+			}
+			data = append(data, old[offset:offset+length]...)
+			p = p[n:]
+		default:
+			return nil, corrupted
+		}
+	}
+	if sum := sha256.Sum256(data); uint64(len(data)) != size || string(sum[:]) != string(want) {
+		return nil, corrupted
+	}
+	return data, nil
+}
+{{end}}{{if .ServeHTTP}}{{if .Precompressed}}
+// {{.Map}}Gzip stores the gzip encodings of the files of {{.Var}} which benefit from compression.
+var {{.Map}}Gzip = map[string]{{if .AsString}}string{{else}}[]byte{{end}}{{"{"}}{{range $name, $data := .Gzip}}
+	{{printf "%#v" $name}}: {{printf "%#v" $data}},{{end}}{{if .Gzip}}
+{{end}}}
+
+// {{.Unexported}}AcceptsGzip reports whether the client accepts gzip encoded responses.
+func {{.Unexported}}AcceptsGzip(r *http.Request) bool {
+	for _, e := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params := e, ""
+		if i := strings.Index(e, ";"); i >= 0 {
+			coding, params = e[:i], e[i+1:]
+		}
+		if strings.TrimSpace(coding) != "gzip" {
+			continue
+		}
+		params = strings.TrimSpace(params)
+		if q, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64); strings.HasPrefix(params, "q=") && err == nil && q == 0 {
+			return false
+		}
+		return true
+	}
+	return false
+}
+{{end}}
+// {{.Map}}ServeHTTP serves the named file with http.ServeContent, which handles
+// byte-range and conditional requests{{if .Times}} using the recorded modification times{{end}},
+// or replies with a 404 error if there is no such file.{{if .Precompressed}}
+// The gzip encoding of the file is sent as is to the clients accepting it.{{end}}
+func {{.Map}}ServeHTTP(w http.ResponseWriter, r *http.Request, name string) {
+	{{if .Compress}}if _, ok := {{.Var}}[name]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+	data, err := {{.Map}}Read(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}{{else}}{{if .Slice}}data, ok := {{.Map}}Lookup(name){{else}}data, ok := {{.Var}}[name]{{end}}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}{{if and .Hook (not .Slice)}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}{{end}}
+	var modTime time.Time{{if .Times}}
+	if t, ok := {{.Map}}ModTimes[name]; ok {
+		modTime = time.Unix(t, 0)
+	}{{end}}{{if .Precompressed}}
+	w.Header().Add("Vary", "Accept-Encoding")
+	if gz, ok := {{.Map}}Gzip[name]; ok && {{.Unexported}}AcceptsGzip(r) {
+		if w.Header().Get("Content-Type") == "" {
+			ctype := mime.TypeByExtension(path.Ext(name))
+			if ctype == "" {
+				n := len(data)
+				if n > 512 {
+					n = 512
+				}
+				ctype = http.DetectContentType({{if .AsString}}[]byte(data[:n]){{else}}data[:n]{{end}})
+			}
+			w.Header().Set("Content-Type", ctype)
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		http.ServeContent(w, r, name, modTime, {{if .AsString}}strings{{else}}bytes{{end}}.NewReader(gz))
+		return
+	}{{end}}
+	http.ServeContent(w, r, name, modTime, {{if and .AsString (not .Compress)}}strings{{else}}bytes{{end}}.NewReader(data))
+}
+{{end}}{{if .SPA}}
+// {{.Map}}Handler returns an HTTP handler serving the files of {{.Var}},
+// falling back to {{printf "%#v" .SPA}} for unknown paths (client-side routing).
+func {{.Map}}Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/"){{if .Compress}}
+		if _, ok := {{.Var}}[name]; !ok {
+			name = {{printf "%#v" .SPA}}
+		}
+		data, err := {{.Map}}Read(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data)){{else}}
+		data, ok := {{.Var}}[name]
+		if !ok {
+			name = {{printf "%#v" .SPA}}
+			data = {{.Var}}[name]
+		}{{if .Hook}}
+		if {{.Map}}OnAccess != nil {
+			{{.Map}}OnAccess(name)
+		}{{end}}
+		http.ServeContent(w, r, name, time.Time{}, {{if .AsString}}strings{{else}}bytes{{end}}.NewReader(data)){{end}}
+	})
+}
+{{end}}{{if .Override}}
+var (
+	{{.Unexported}}OverrideOnce sync.Once
+	{{.Unexported}}OverrideFS   fs.FS
+)
+
+// {{.Map}}Override returns the file system whose files replace those of {{.Var}}:
+{{if .OverrideKey}}// the zip archive named by the {{.Override}} environment variable, opened on first
+// use, or nil if the variable is not set or the archive is not properly signed.{{else}}// the directory or zip archive named by the {{.Override}} environment variable,
+// opened on first use, or nil if the variable is not set or the archive cannot be opened.{{end}}
+func {{.Map}}Override() fs.FS {
+	{{.Unexported}}OverrideOnce.Do(func() {
+		dir := os.Getenv({{printf "%#v" .Override}})
+		switch {
+		case dir == "":
+		case strings.HasSuffix(strings.ToLower(dir), ".zip"):
+			{{if .OverrideKey}}if r := {{.Unexported}}OpenSigned(dir); r != nil {
+				{{.Unexported}}OverrideFS = r
+			}
+		default: // directories cannot be signed{{else}}if r, err := zip.OpenReader(dir); err == nil {
+				{{.Unexported}}OverrideFS = r
+			}
+		default:
+			{{.Unexported}}OverrideFS = os.DirFS(dir){{end}}
+		}
+	})
+	return {{.Unexported}}OverrideFS
+}
+{{if .OverrideKey}}
+// {{.Unexported}}OverrideKey is the public key verifying the signatures of the
+// archives overriding the files of {{.Var}}.
+var {{.Unexported}}OverrideKey = ed25519.PublicKey({{printf "%#v" .OverrideKey}})
+
+// {{.Unexported}}OpenSigned returns the files of the zip archive at path if it is
+// signed by {{.Unexported}}OverrideKey, the signature being read from path.sig,
+// or nil otherwise. The archive is read in memory, so that its files are
+// those whose signature was verified.
+func {{.Unexported}}OpenSigned(path string) *zip.Reader {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	sig, err := os.ReadFile(path + ".sig")
+	if err != nil || !ed25519.Verify({{.Unexported}}OverrideKey, data, sig) {
+		return nil
+	}
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil
+	}
+	return r
+}
+{{end}}
+// {{.Map}}Data returns a copy of the contents of the named file, read from
+// {{.Map}}Override if it has the file, or false if there is no such file.
+func {{.Map}}Data(name string) ([]byte, bool) {
+	data, ok := {{.Unexported}}Embedded(name)
+	if o := {{.Map}}Override(); ok && o != nil {
+		if data, err := fs.ReadFile(o, name); err == nil {
+			return data, true
+		}
+	}
+	return data, ok
+}
+{{end}}{{if or .FS .Iter .Localized .Meta .Typed .Templates .Override .AssetError .AssetPanic .Register}}
+{{if .Override}}// {{.Unexported}}Embedded returns a copy of the contents of the named embedded file,
+// or false if there is no such file.
+func {{.Unexported}}Embedded(name string) ([]byte, bool) {{"{"}}{{else}}// {{.Map}}Data returns a copy of the contents of the named file,
+// or false if there is no such file.
+func {{.Map}}Data(name string) ([]byte, bool) {{"{"}}{{end}}
+	{{if .Compress}}if _, ok := {{.Var}}[name]; !ok {
+		return nil, false
+	}
+	data, err := {{.Map}}Read(name)
+	if err != nil {
+		return nil, false
+	}
+	return data, true{{else}}{{if .Slice}}data, ok := {{.Map}}Lookup(name){{else}}data, ok := {{.Var}}[name]{{end}}
+	if !ok {
+		return nil, false
+	}{{if and .Hook (not .Slice)}}
+	if {{.Map}}OnAccess != nil {
+		{{.Map}}OnAccess(name)
+	}{{end}}
+	return {{if .AsString}}[]byte(data){{else}}append([]byte{}, data...){{end}}, true{{end}}
+}
+{{end}}{{$name := "name"}}{{if .Names}}{{$name = "string(name)"}}{{end}}{{if .AssetError}}
+// {{.Map}}Asset returns a copy of the contents of the named file,
+// or an error if there is no such file.
+func {{.Map}}Asset(name {{if .Names}}{{.Map}}Name{{else}}string{{end}}) ([]byte, error) {
+	data, ok := {{.Map}}Data({{$name}})
+	if !ok {
+		return nil, {{.Map}}ErrAssetNotFound{Name: {{$name}}}
+	}
+	return data, nil
+}
+{{end}}{{if .AssetPanic}}
+// {{.Map}}MustAsset returns a copy of the contents of the named file,
+// and panics if there is no such file.
+func {{.Map}}MustAsset(name {{if .Names}}{{.Map}}Name{{else}}string{{end}}) []byte {
+	data, ok := {{.Map}}Data({{$name}})
+	if !ok {
+		panic({{.Map}}ErrAssetNotFound{Name: {{$name}}})
+	}
+	return data
+}
+{{end}}{{with .Register}}
+func init() {
+	runtime.Register(runtime.Bundle{
+		Name: {{printf "%q" .}},
+		Names: []string{{"{"}}{{range $name, $_ := $.Files}}
+			{{printf "%#v" $name}},{{end}}
+		},
+		Read: {{$.Map}}Data,
+	})
+}
+{{end}}{{if .Sizes}}
+// {{.Unexported}}Sizes maps the files of {{.Var}} to their sizes and stored sizes,
+// which differ for compressed files.
+var {{.Unexported}}Sizes = map[string][2]int64{{"{"}}{{range aligned .FileSizes nil}}
+	{{.Key}}{{"{"}}{{index .Value 0}}, {{index .Value 1}}},{{end}}{{if .FileSizes}}
+{{end}}}
+
+// {{.Map}}Size returns the size of the named file once decompressed,
+// or -1 if there is no such file.
+func {{.Map}}Size(name string) int64 {
+	if s, ok := {{.Unexported}}Sizes[name]; ok {
+		return s[0]
+	}
+	return -1
+}
+
+// {{.Map}}StoredSize returns the size of the named file as stored in the binary,
+// compressed or not, or -1 if there is no such file.
+func {{.Map}}StoredSize(name string) int64 {
+	if s, ok := {{.Unexported}}Sizes[name]; ok {
+		return s[1]
+	}
+	return -1
+}
+
+// {{.Map}}TotalSize returns the total size of the files once decompressed.
+func {{.Map}}TotalSize() int64 {
+	return {{.TotalSize}}
+}
+{{end}}{{if .Stat}}
+// {{.Map}}AssetInfo describes a file of {{.Var}}.
+type {{.Map}}AssetInfo struct {
+	Name    string
+	Size    int64       // size once decompressed
+	Mode    fs.FileMode // permissions, 0644 unless recorded
+	ModTime time.Time   // modification time, zero unless recorded
+	Hash    [32]byte    // SHA-256 checksum of the contents{{if .GitMeta}}
+
+	Commit     string    // hash of the last git commit of the file, empty if not committed
+	CommitTime time.Time // committer date of the last commit
+	Author     string    // author of the last commit{{end}}
+}
+
+// {{.Unexported}}Infos maps the files of {{.Var}} to their descriptions.
+var {{.Unexported}}Infos = map[string]{{.Map}}AssetInfo{{"{"}}{{range aligned .Infos nil}}{{$i := .Value}}
+	{{.Key}}{Name: {{printf "%#v" .Name}}, Size: {{$i.Size}}, Mode: {{printf "%#o" $i.Mode}}{{if $i.ModTime}}, ModTime: time.Unix({{$i.ModTime}}, 0){{end}}, Hash: {{$i.Hash}}{{with $i.Commit.Hash}}, Commit: {{printf "%#v" .}}, CommitTime: time.Unix({{$i.Commit.Time}}, 0), Author: {{printf "%#v" $i.Commit.Author}}{{end}}},{{end}}{{if .Infos}}
+{{end}}}
+
+// {{.Map}}Stat returns the description of the named file, or false if there is no such file.
+func {{.Map}}Stat(name string) ({{.Map}}AssetInfo, bool) {
+	info, ok := {{.Unexported}}Infos[name]
+	return info, ok
+}
+{{end}}{{if .Meta}}
+// {{.Map}}Modes maps the files of {{.Var}} to their permissions, 0644 if absent.
+var {{.Map}}Modes = map[string]fs.FileMode{{"{"}}{{range aligned .Modes nil}}
+	{{.Key}}{{printf "%#o" .Value}},{{end}}{{if .Modes}}
+{{end}}}
+{{if .Times}}
+// {{.Map}}ModTimes maps the files of {{.Var}} to their modification times,
+// in seconds since the Unix epoch.
+var {{.Map}}ModTimes = map[string]int64{{"{"}}{{range aligned .ModTimes nil}}
+	{{.Key}}{{.Value}},{{end}}{{if .ModTimes}}
+{{end}}}
+{{end}}
+// {{.Map}}Restore writes the files of {{.Var}} to dir with their permissions{{if .Times}}
+// and modification times{{end}}, creating the directories as needed.
+func {{.Map}}Restore(dir string) error {
+	{{if .Slice}}for _, file := range {{.Var}} {
+		name := file.Name{{else}}for name := range {{.Var}} {{"{"}}{{end}}
+		path := filepath.FromSlash(name)
+		if !filepath.IsLocal(path) {
+			return errors.New("{{.Map}}: invalid file name: " + name)
+		}
+		path = filepath.Join(dir, path)
+		mode, ok := {{.Map}}Modes[name]
+		if !ok {
+			mode = 0644
+		}
+		data, _ := {{.Map}}Data(name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, mode); err != nil {
+			return err
+		}
+		if err := os.Chmod(path, mode); err != nil { // ignore the umask
+			return err
+		}{{if .Times}}
+		if t, ok := {{.Map}}ModTimes[name]; ok {
+			if err := os.Chtimes(path, time.Unix(t, 0), time.Unix(t, 0)); err != nil {
+				return err
+			}
+		}{{end}}
+	}
+	return nil
+}
+{{end}}{{if .Localized}}
+// {{.Map}}Localized returns a copy of the contents of the variant of the named
+// file for the given locale, e.g. "messages.fr-CA.json" for "messages.json"
+// and "fr-CA", falling back to less specific locales ("messages.fr.json"),
+// to the default locale ({{printf "%#v" .Localized}}) and finally to the file itself.
+// It returns false if there is no such file.
+func {{.Map}}Localized(name, locale string) ([]byte, bool) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for _, l := range []string{strings.ReplaceAll(locale, "_", "-"), {{printf "%#v" .Localized}}} {
+		for l != "" {
+			if data, ok := {{.Map}}Data(base + "." + l + ext); ok {
+				return data, true
+			}
+			i := strings.LastIndex(l, "-")
+			if i < 0 {
+				break
+			}
+			l = l[:i]
+		}
+	}
+	return {{.Map}}Data(name)
+}
+{{end}}{{if .Typed}}
+// {{.Unexported}}DecodedKey identifies a file decoded into a value of some type.
+type {{.Unexported}}DecodedKey struct {
+	name string
+	typ  reflect.Type
+}
+
+// {{.Unexported}}Decoded caches the values returned by {{.Map}}AssetAs.
+var (
+	{{.Unexported}}DecodedMu sync.Mutex
+	{{.Unexported}}Decoded   = make(map[{{.Unexported}}DecodedKey]any)
+)
+
+// {{.Map}}AssetAs returns the contents of the named file decoded by decode, e.g.
+//
+//	config, err := {{.Map}}AssetAs("config.json", {{.Map}}JSON[Config])
+//
+// A file is decoded once per type: the values are cached and shared by all
+// the callers, so they must not be modified.
+func {{.Map}}AssetAs[T any](name string, decode func([]byte) (T, error)) (T, error) {
+	key := {{.Unexported}}DecodedKey{name, reflect.TypeOf((*T)(nil)).Elem()}
+	{{.Unexported}}DecodedMu.Lock()
+	defer {{.Unexported}}DecodedMu.Unlock()
+	if v, ok := {{.Unexported}}Decoded[key]; ok {
+		return v.(T), nil
+	}
+	var v T
+	data, ok := {{.Map}}Data(name)
+	if !ok {
+		return v, {{.Map}}ErrAssetNotFound{Name: name}
+	}
+	v, err := decode(data)
+	if err != nil {
+		return v, {{.Map}}ErrDecode{Name: name, Err: err}
+	}
+	{{.Unexported}}Decoded[key] = v
+	return v, nil
+}
+{{range .Decoders}}{{if eq . "json"}}
+// {{$.Map}}JSON decodes JSON data, for use with {{$.Map}}AssetAs.
+func {{$.Map}}JSON[T any](data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+{{else if eq . "yaml"}}
+// {{$.Map}}YAML decodes YAML data, for use with {{$.Map}}AssetAs.
+func {{$.Map}}YAML[T any](data []byte) (T, error) {
+	var v T
+	err := yaml.Unmarshal(data, &v)
+	return v, err
+}
+{{end}}{{end}}{{end}}{{if .Templates}}
+// {{.Map}}TemplateFuncs are the functions available to the templates parsed by
+// {{.Map}}Templates. They must be set before its first call.
+var {{.Map}}TemplateFuncs template.FuncMap
+
+// {{.Map}}TemplateFiles are the files of {{.Var}} parsed by {{.Map}}Templates.
+var {{.Map}}TemplateFiles = []string{{"{"}}{{range .TemplateFiles}}
+	{{printf "%#v" .}},{{end}}{{if .TemplateFiles}}
+{{end}}}
+
+var (
+	{{.Unexported}}TemplatesOnce sync.Once
+	{{.Unexported}}TemplatesTree *template.Template
+	{{.Unexported}}TemplatesErr  error
+)
+
+// {{.Map}}Templates returns a tree of the templates of {{.Map}}TemplateFiles, each
+// named after its file, parsed once on the first call (e.g. from an init function
+// to fail early). The tree is shared: it must be cloned before being modified.
+func {{.Map}}Templates() (*template.Template, error) {
+	{{.Unexported}}TemplatesOnce.Do(func() {
+		t := template.New("").Funcs({{.Map}}TemplateFuncs)
+		for _, name := range {{.Map}}TemplateFiles {
+			data, _ := {{.Map}}Data(name)
+			if _, err := t.New(name).Parse(string(data)); err != nil {
+				{{.Unexported}}TemplatesErr = err
+				return
+			}
+		}
+		{{.Unexported}}TemplatesTree = t
+	})
+	return {{.Unexported}}TemplatesTree, {{.Unexported}}TemplatesErr
+}
+{{end}}{{if .Migrate}}
+// {{.Map}}Migration is a database migration embedded in {{.Var}}.
+type {{.Map}}Migration struct {
+	Version     uint64
+	Description string
+	Up, Down    string // names of the files, Down empty if there is none
+}
+
+// {{.Map}}Migrations are the migrations embedded in {{.Var}} by increasing version.
+// Their files can also be read through {{.Map}}FS, e.g. by the migration
+// libraries reading an fs.FS.
+var {{.Map}}Migrations = []{{.Map}}Migration{{"{"}}{{range .Migrations}}
+	{ {{- printf "%d, %#v, %#v, %#v" .Version .Description .Up .Down -}} },{{end}}{{if .Migrations}}
+{{end}}}
+{{end}}{{if .FS}}
+// {{.Map}}FS is a read-only file system of the files of {{.Var}},
+// with the same methods as embed.FS.
+type {{.Map}}FS struct{}
+
+// Open opens the named file or directory.
+func (f {{.Map}}FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if data, ok := {{.Map}}Data(name); ok {
+		return &{{.Map}}FSFile{info: {{.Map}}FSInfo{path: name}, Reader: bytes.NewReader(data)}, nil
+	}
+	entries, err := f.ReadDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &{{.Map}}FSFile{info: {{.Map}}FSInfo{path: name, dir: true}, Reader: bytes.NewReader(nil), entries: entries}, nil
+}
+
+// ReadFile returns a copy of the contents of the named file.
+func (f {{.Map}}FS) ReadFile(name string) ([]byte, error) {
+	data, ok := {{.Map}}Data(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return data, nil
+}
+
+// ReadDir returns the entries of the named directory sorted by name.
+func (f {{.Map}}FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	{{if .Slice}}for _, file := range {{.Var}} {
+		key := file.Name{{else}}for key := range {{.Var}} {{"{"}}{{end}}
+{{if lt .Go 20}}		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		base := key[len(prefix):]
+		i := strings.Index(base, "/")
+		dir := i >= 0
+		if dir {
+			base = base[:i]
+		}
+{{else}}		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		base, _, dir := strings.Cut(rest, "/")
+{{end}}		if !seen[base] {
+			seen[base] = true
+			entries = append(entries, {{.Map}}FSInfo{path: prefix + base, dir: dir})
+		}
+	}
+	if len(entries) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// {{.Map}}FSInfo describes a file or directory of {{.Map}}FS.
+type {{.Map}}FSInfo struct {
+	path string
+	dir  bool
+}
+
+func (i {{.Map}}FSInfo) Name() string               { return path.Base(i.path) }
+func (i {{.Map}}FSInfo) IsDir() bool                { return i.dir }
+{{if not .Times}}func (i {{.Map}}FSInfo) ModTime() time.Time         { return time.Time{} }
+{{end}}func (i {{.Map}}FSInfo) Sys() {{if lt .Go 18}}interface{}           {{else}}any                   {{end}}{ return nil }
+func (i {{.Map}}FSInfo) Type() fs.FileMode          { return i.Mode().Type() }
+func (i {{.Map}}FSInfo) Info() (fs.FileInfo, error) { return i, nil }
+
+// Mode returns the mode of the file or directory, read-only.
+func (i {{.Map}}FSInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+{{if .Times}}
+// ModTime returns the modification time of the file, zero for a directory.
+func (i {{.Map}}FSInfo) ModTime() time.Time {
+	if t, ok := {{.Map}}ModTimes[i.path]; ok {
+		return time.Unix(t, 0)
+	}
+	return time.Time{}
+}
+{{end}}
+// Size returns the size of the file.
+func (i {{.Map}}FSInfo) Size() int64 {
+	data, _ := {{.Map}}Data(i.path)
+	return int64(len(data))
+}
+
+// {{.Map}}FSFile is an open file or directory of {{.Map}}FS.
+type {{.Map}}FSFile struct {
+	*bytes.Reader
+	info    {{.Map}}FSInfo
+	entries []fs.DirEntry // remaining entries of a directory
+}
+
+func (f *{{.Map}}FSFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *{{.Map}}FSFile) Close() error               { return nil }
+
+// ReadDir returns the next n entries of a directory, or all the remaining ones if n <= 0.
+func (f *{{.Map}}FSFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !f.info.dir {
+		return nil, &fs.PathError{Op: "readdir", Path: f.info.path, Err: errors.New("not a directory")}
+	}
+	if n <= 0 || n > len(f.entries) {
+		if n > 0 && len(f.entries) == 0 {
+			return nil, io.EOF
+		}
+		n = len(f.entries)
+	}
+	entries := f.entries[:n]
+	f.entries = f.entries[n:]
+	return entries, nil
+}
+{{if .Walk}}
+// {{.Map}}Walk walks the files of {{.Var}} under root, "." for all of them, with
+// the semantics of fs.WalkDir: fn is called for each file and directory in
+// lexical order, and can return fs.SkipDir or fs.SkipAll.
+func {{.Map}}Walk(root string, fn fs.WalkDirFunc) error {
+	return fs.WalkDir({{.Map}}FS{}, root, fn)
+}
+{{end}}{{end}}{{if .Iter}}
+// {{.Map}}All returns an iterator over the names and contents of the files,
+// sorted by name. The contents are copies{{if .Compress}}, decompressed{{end}} as the iteration
+// proceeds, so that breaking out of the loop skips the remaining files.
+func {{.Map}}All() iter.Seq2[string, []byte] {
+	return func(yield func(string, []byte) bool) {
+		{{if .Slice}}for _, file := range {{.Var}} {
+			name := file.Name{{else}}for _, name := range slices.Sorted(maps.Keys({{.Var}})) {{"{"}}{{end}}
+			if data, ok := {{.Map}}Data(name); ok && !yield(name, data) {
+				return
+			}
+		}
+	}
+}
+{{end}}{{with .Tree}}
+// {{.Name}} gives access to the files of {{$.Var}} by path, e.g. {{.Name}}.Dir.FileExt()
+// for "dir/file.ext".
+var {{.Name}} {{(index .Dirs 0).Type}}
+{{range $dir := .Dirs}}
+// {{.Type}} is the directory {{printf "%#v" .Path}} of {{$.Tree.Name}}.
+type {{.Type}} struct {{"{"}}{{range .Subdirs}}
+	{{printf "%-*s" $dir.Width .Name}} {{.Type}}{{end}}
+}
+{{range .Files}}
+// {{.Name}} returns the contents of {{printf "%#v" .Key}}.
+func ({{$dir.Type}}) {{.Name}}() {{if $.AsString}}string{{else}}[]byte{{end}} {
+	{{if $.PerFile}}return {{$.Unexported}}_{{index $.Idents .Key}}{{else if $.Slice}}data, _ := {{$.Map}}Lookup({{printf "%#v" .Key}})
+	return data{{else}}{{if $.Hook}}if {{$.Map}}OnAccess != nil {
+		{{$.Map}}OnAccess({{printf "%#v" .Key}})
+	}
+	{{end}}return {{$.Var}}[{{printf "%#v" .Key}}]{{end}}
+}
+{{end}}{{end}}{{end}}{{if .Receiver}}
+// {{.Receiver}} gives access to the files of {{.Var}} with a method per file,
+// e.g. {{.Receiver}}{}.DirFileExt() for "dir/file.ext".
+type {{.Receiver}} struct{}
+{{range .Methods}}
+// {{.Name}} returns the contents of {{printf "%#v" .Key}}.
+func ({{$.Receiver}}) {{.Name}}() {{if $.AsString}}string{{else}}[]byte{{end}} {
+	{{if $.PerFile}}return {{$.Unexported}}_{{index $.Idents .Key}}{{else if $.Slice}}data, _ := {{$.Map}}Lookup({{printf "%#v" .Key}})
+	return data{{else}}{{if $.Hook}}if {{$.Map}}OnAccess != nil {
+		{{$.Map}}OnAccess({{printf "%#v" .Key}})
+	}
+	{{end}}return {{$.Var}}[{{printf "%#v" .Key}}]{{end}}
+}
+{{end}}{{end}}{{if .Consts}}{{if .Names}}
+// {{.Map}}Name is the name of a file stored in {{.Map}}, one of the constants below.
+type {{.Map}}Name string
+{{end}}
+// Names of the files stored in {{.Map}}.
+const ({{range .Consts}}
+	{{printf "%-*s" $.ConstWidth .Name}}{{if $.Names}} {{$.Map}}Name{{end}} = {{printf "%#v" .Value}}{{end}}
+)
+{{end}}{{if .Version}}
+// {{.Map}}Version is a fingerprint of the names and contents of the files stored in {{.Map}}.
+const {{.Map}}Version = {{printf "%#v" .Version}}
+{{end}}{{if .Hashed}}
+// {{.Map}}Hashed maps the file names to their content-addressed keys in {{.Map}}.
+var {{.Map}}Hashed = map[string]string{{"{"}}{{range aligned .Hashed nil}}
+	{{.Key}}{{printf "%#v" .Value}},{{end}}{{if .Hashed}}
+{{end}}}
+
+// {{.Map}}Rewrite replaces the file names referenced in s (e.g. in HTML or CSS)
+// with their content-addressed keys in {{.Map}}.
+func {{.Map}}Rewrite(s string) string {
+	return {{.Map}}Rewriter.Replace(s)
+}
+
+// {{.Map}}Rewriter replaces the file names with their content-addressed keys, longest names first.
+var {{.Map}}Rewriter = strings.NewReplacer({{range .HashedOrder}}
+	{{printf "%#v" .}}, {{printf "%#v" (index $.Hashed .)}},{{end}}
+)
+{{end}}{{.Footer}}`
+
+// runtimePkg is the import path of the registry of the bundles (-register).
+const runtimePkg = "github.com/simleb/bindata/runtime"
+
+// templateVars contains the variables required by the template.
+type templateVars struct {
+	Generated string // comment marking the generated files
+	Header    string // text inserted at the top of the generated files
+	Footer    string // text appended to the generated files
+
+	Pkg      string
+	Doc      []string // lines of the package documentation listing the files
+	Imports  []string
+	Map      string
+	Var      string // name of the map variable, different from Map if ReadOnly or BytesViaString
+	AsString bool
+	ReadOnly bool
+	Slice    bool   // store the files in a sorted slice instead of a map
+	PerFile  bool   // store each file in its own variable instead of a map
+	SPA      string // key of the fallback file of the HTTP handler
+	FS       bool   // generate a file system with the methods of embed.FS
+	Walk     bool   // generate a function walking the files like fs.WalkDir
+	Iter     bool   // generate the iterator over the files (range-over-func)
+	Files    map[string]fmt.Formatter
+	Comments map[string]string // comments of the files indexed by key
+	Idents   map[string]string // identifiers of the files indexed by key, with PerFile
+
+	Unexported string // Map with a lower case first letter, prefix of the unexported names
+
+	Localized string // default locale of the localized accessor, if any
+	Hook      bool   // call a hook on each access through the generated functions
+	Errors    bool   // generate the error types returned by the accessors
+	Writer    bool   // generate the function streaming the files to a writer
+	Preload   bool   // generate the functions decompressing the files in advance
+	ServeHTTP bool   // generate the function serving the files over HTTP
+	Go        int    // minor version of Go targeted by the generated code
+
+	Precompressed bool                     // serve the gzip encodings of the files
+	Gzip          map[string]fmt.Formatter // gzip encodings of the files indexed by key
+
+	Templates     string   // package parsing the templates, text or html, if any
+	TemplateFiles []string // keys of the templates
+
+	Override    string // environment variable naming the files overriding the embedded ones
+	OverrideKey []byte // public key verifying the signatures of the override archives, if any
+	Register    string // name of the bundle in the registry of the runtime package, if any
+
+	Sizes     bool              // generate the functions returning the sizes of the files
+	FileSizes map[string][2]int // sizes and stored sizes of the files indexed by key
+	TotalSize int               // total size of the files
+
+	Stat    bool                 // generate the function describing the files
+	GitMeta bool                 // describe the last git commits of the files
+	Infos   map[string]assetInfo // descriptions of the files indexed by key
+
+	Patch bool // generate the function applying binary patches to the files
+
+	Salt    string // salt of the hashes hiding the file names, if any
+	SaltLen int    // number of bytes of the hashes hiding the file names
+
+	Migrate    bool        // embed SQL migrations and list them
+	Migrations []Migration // migrations by increasing version
+
+	Tree *Tree // types giving access to the files by path, if any
+
+	Receiver string      // type with a method per file, if any
+	Methods  []treeEntry // methods of the receiver type, sorted by key
+
+	AssetError bool // generate the accessor returning an error for missing files
+	AssetPanic bool // generate the accessor panicking on missing files
+	Names      bool // type the constants of the file names and the arguments of the accessors
+
+	Typed    bool     // generate the generic accessor decoding the files
+	Decoders []string // built-in decoders for the generic accessor: json, yaml
+
+	Meta     bool                   // record the permissions of the files
+	Modes    map[string]os.FileMode // permissions of the files indexed by key, if not 0644
+	Times    bool                   // record the modification times of the files
+	ModTimes map[string]int64       // modification times of the files indexed by key, if known
+
+	Blob    fmt.Formatter     // concatenated contents of the files, if stored in a blob
+	Offsets map[string][2]int // offsets and sizes of the files in the blob indexed by key
+
+	Aliases map[string]string // keys of the files indexed by alias, added to the map on initialization
+
+	SpillOver Size              // size beyond which the compressed files are decompressed to disk
+	Spilled   map[string]string // SHA-256 checksums of the files decompressed to disk indexed by key
+
+	EmbedOver Size              // size beyond which the files are embedded with go:embed
+	EmbedDir  string            // directory of the files embedded with go:embed, next to the output file
+	Embedded  map[string]string // names of the files embedded with go:embed indexed by key
+
+	BytesViaString bool
+
+	Compress string            // codec compressing the files, if any
+	Codecs   map[string]string // codecs of the compressed files indexed by key
+
+	Consts     []constant
+	ConstWidth int
+
+	Version string // fingerprint of the files
+
+	Hashed      map[string]string // content-addressed keys indexed by file names
+	HashedOrder []string          // file names by decreasing length
+}
+
+// An Asset is a file to embed.
+type Asset struct {
+	Name string      // key in the map
+	Path string      // path of the source file
+	Data []byte      // contents of the file, after transforms
+	Mode os.FileMode // permissions of the source file
+	Time time.Time   // modification time of the source file, if known
+
+	// Lazy is set if the contents are not held in memory but streamed
+	// from the source file to the output file (-max-mem).
+	Lazy bool
+	size int // size of the contents if Lazy
+
+	sum    *[sha256.Size]byte // SHA-256 checksum of the contents, once computed
+	xxhash *uint64            // XXH64 hash of the contents, once computed
+}
+
+// Len returns the size of the contents of the asset, held in memory or not.
+func (a *Asset) Len() int {
+	if a.Lazy {
+		return a.size
+	}
+	return len(a.Data)
+}
+
+// Comment describes the origin of the asset, its size and hash,
+// and its stored size if it is compressed.
+func (a *Asset) Comment(codec string, stored int) string {
+	origin := a.Path
+	if origin == "" {
+		origin = a.Name
+	}
+	sum := a.SHA256()
+	c := fmt.Sprintf("%s: %d bytes", commentSafe(filepath.ToSlash(origin)), len(a.Data))
+	if codec != "" {
+		c += fmt.Sprintf(" (%s: %d bytes)", codec, stored)
+	}
+	return c + ", sha256 " + hex.EncodeToString(sum[:])
+}
+
+// commentSafe returns a file name as is if it can appear in a line comment,
+// or quoted as a Go string if it is not valid UTF-8 or holds characters
+// which are not printable, such as newlines.
+func commentSafe(name string) string {
+	if utf8.ValidString(name) && !strings.ContainsFunc(name, func(r rune) bool { return !strconv.IsPrint(r) }) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// Main runs the bindata command with the arguments of its command line,
+// without the name of the program, until ctx is done. It prints the error of
+// the command, if any, and returns the exit status of the program.
+func Main(ctx context.Context, args []string) int {
+	if err := run(ctx, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0 // usage already printed
+		}
+		if errors.As(err, new(loggedError)) {
+			return 1
+		}
+		w := io.Writer(os.Stdout)
+		if errors.As(err, new(quietError)) {
+			w = os.Stderr // nothing but generated code on stdout
+		}
+		fmt.Fprintln(w, "bindata:", err)
+		return 1
+	}
+	return 0
+}
+
+// run executes the subcommand of args, or a generation by default.
+func run(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "init":
+			return runInit(args[1:])
+		case "serve":
+			return runServe(ctx, args[1:])
+		case "inspect":
+			return runInspect(args[1:])
+		case "diff":
+			return runDiff(args[1:])
+		case "validate":
+			return runValidate(args[1:])
+		case "verify-file":
+			return runVerifyFile(args[1:])
+		case "budget-check":
+			return runBudgetCheck(args[1:])
+		case "patch":
+			return runPatch(args[1:])
+		case "sign":
+			return runSign(args[1:])
+		case "pin":
+			return runPin(args[1:])
+		}
+	}
+	return Generate(ctx, args, nil)
+}
+
+// Generate generates the output files from the arguments of a command line,
+// without the name of the program, as the bindata command does, embedding
+// the files of sources along with the inputs of the command line.
+// Generations are independent and can run concurrently.
+// It stops as soon as ctx is done, while walking directories or reading files,
+// and returns the error of ctx.
+// If progress is not nil, it is called after each file added to the assets.
+func Generate(ctx context.Context, args []string, progress func(Progress), sources ...Source) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	gen := newGenerator(ctx, progress)
+	gen.sources = sources
+	if err := gen.run(args); err != nil {
+		if gen.log.json {
+			gen.log.log(Event{Level: "error", Kind: "error", Message: err.Error()})
+			return loggedError{err}
+		}
+		if gen.quiet {
+			return quietError{err}
+		}
+		return err
+	}
+	return nil
+}
+
+// A quietError is an error of a generation writing its output to stdout
+// with -o -, to be printed on stderr.
+type quietError struct{ error }
+
+// Unwrap returns the error of the generation.
+func (e quietError) Unwrap() error { return e.error }
+
+// run generates the output file from the command line arguments.
+func (gen *generator) run(args []string) error {
+	// use GOPACKAGE (set by go generate) as default package name if available
+	pkg := os.Getenv("GOPACKAGE")
+	if pkg == "" {
+		pkg = "main"
+	}
+
+	var out, prefix, constPrefix, configFile, profile, header, footer, reportFile, provenanceFile, jsonManifest, tsManifest, logFormat, lockFile string
+	var budget, embedOver, spillOver Size
+	var copies, goPkgs, layers Paths
+	var secretAllow Patterns
+	var force, forceWrite, updateLock, skipEmpty, perDir, hashNames, gitignore, fromArchive, comments, version, doc, verbose, phases, checksum bool
+	var cacheHash, secretScan, overrideKey, spa, compress, target, layout, abs, meta, lang, typed, templates, tree, naming, accessors, salt, emit, customTemplate, mirror string
+	// errors are returned rather than exiting, generations running in the server too
+	fs := flag.NewFlagSet("bindata", flag.ContinueOnError)
+	fs.StringVar(&out, "o", "", "output file, - for stdout without diagnostics (default: stdout)")
+	fs.BoolVar(&force, "force", false, "overwrite output files even if they do not look generated by bindata")
+	fs.BoolVar(&forceWrite, "force-write", false, "write output files even if their contents are unchanged")
+	fs.Var(&copies, "o-copy", "also write the output file to this path, with identical contents (repeatable)")
+	fs.StringVar(&emit, "emit", "go", "language of the output file: go, c (header of unsigned char arrays) or json (base64 contents)")
+	fs.StringVar(&customTemplate, "t", "", "render the output file with this text/template instead of generating Go code")
+	fs.StringVar(&gen.vars.Pkg, "p", pkg, "name of the package")
+	fs.StringVar(&gen.vars.Generated, "generated", defaultGenerated, "comment marking the generated files (empty for none)")
+	fs.StringVar(&header, "header", "", "file whose contents are inserted at the top of the generated files")
+	fs.StringVar(&footer, "footer", "", "file whose contents are appended to the generated files")
+	fs.BoolVar(&checksum, "checksum", false, "end the generated files with a comment recording their checksum (see verify-file)")
+	fs.StringVar(&gen.vars.Map, "m", "bindata", "name of the map variable")
+	fs.StringVar(&prefix, "r", "", "root path for map keys")
+	fs.StringVar(&abs, "abs", "reject", "policy for keys absolute or outside of the root: reject, trim or keep")
+	fs.BoolVar(&gen.vars.AsString, "s", false, "save data as strings")
+	fs.IntVar(&gen.columns, "columns", 0, "number of bytes per line of the contents of the files (default: 12, or 16 for strings)")
+	fs.StringVar(&gen.tab, "tab", DefaultTab, "indentation of the lines of the contents of the files within their value: tabs or spaces")
+	fs.BoolVar(&gen.uppercase, "uppercase", false, "print the hexadecimal digits of the contents of the files in upper case")
+	fs.StringVar(&compress, "compress", "", "compress the files that benefit from it with this codec: gzip, flate or auto (the smallest per file)")
+	fs.BoolVar(&gen.vars.FS, "fs", false, "generate a file system type with the methods of embed.FS")
+	fs.BoolVar(&gen.vars.Walk, "walk", false, "generate a function walking the files with the semantics of fs.WalkDir (implies -fs)")
+	fs.BoolVar(&gen.vars.Iter, "iter", false, "generate a function returning an iterator over the names and contents of the files (Go 1.23)")
+	fs.StringVar(&gen.vars.Localized, "localized", "", "generate an accessor of localized variants (name.locale.ext) falling back to this locale")
+	fs.StringVar(&meta, "meta", "", "record metadata of the files (comma-separated): permissions (exec for the executable bit only, or mode) and modification times (time)")
+	fs.StringVar(&accessors, "accessors", "", "generate accessors of the files (comma-separated): error (Asset returning an error), panic (MustAsset) and const (only accepting the constants of -const-prefix)")
+	fs.StringVar(&typed, "typed", "", "generate a generic accessor decoding the files, with these built-in decoders: json, yaml or none (comma-separated)")
+	fs.StringVar(&templates, "templates", "", "generate a function parsing the .tmpl and .gotmpl files with this package: text or html")
+	fs.BoolVar(&gen.vars.Migrate, "migrations", false, "embed only the .sql files and list the migrations they define, with a file system (implies -fs)")
+	fs.StringVar(&gen.vars.Override, "override", "", "let the directory or zip archive named by this environment variable override the files at run time")
+	fs.StringVar(&overrideKey, "override-key", "", "only let zip archives signed by the private key of this hex-encoded Ed25519 public key override the files (see sign)")
+	fs.StringVar(&gen.vars.Register, "register", "", "register the files under this bundle name in the registry of "+runtimePkg)
+	fs.BoolVar(&gen.vars.Precompressed, "precompressed", false, "store the gzip encodings of the files and serve them to the clients accepting them (implies -serve-http)")
+	fs.BoolVar(&gen.vars.ServeHTTP, "serve-http", false, "generate a function serving the files over HTTP with http.ServeContent, including range requests")
+	fs.BoolVar(&gen.vars.Stat, "stat", false, "generate a function describing a file: name, size, mode, modification time and SHA-256 checksum")
+	fs.BoolVar(&gen.vars.Patch, "patches", false, "generate a function applying binary patches made by bindata patch to the files")
+	fs.BoolVar(&gen.vars.GitMeta, "git-meta", false, "add the hash, date and author of the last git commit of each file to the descriptions of -stat")
+	fs.BoolVar(&gen.vars.Sizes, "sizes", false, "generate functions returning the sizes of the files, decompressed and stored, and their total size")
+	fs.BoolVar(&gen.vars.Preload, "preload", false, "generate functions decompressing compressed files in advance, in the background")
+	fs.Var(&spillOver, "spill-over", "decompress the compressed files larger than this size (e.g. 64MB) to a cache directory on first access and read them from there")
+	fs.BoolVar(&gen.vars.Writer, "writer", false, "generate a function streaming the files to a writer, decompressed on the fly")
+	fs.BoolVar(&gen.vars.Hook, "hook", false, "generate a hook called with the name of each file accessed through the generated functions")
+	fs.StringVar(&spa, "spa", "", "generate an HTTP handler falling back to this file for unknown paths")
+	fs.StringVar(&layout, "layout", "map", "data structure storing the files: map, slice (sorted, searched by a function), blob (slice of offsets in a single string) or vars (a variable and an accessor function per file)")
+	fs.StringVar(&target, "target", "", "generate code suited to a target compiler (tinygo)")
+	fs.StringVar(&lang, "lang", "", "oldest Go version the generated code must compile with (e.g. 1.16, default: latest)")
+	fs.BoolVar(&gen.vars.ReadOnly, "readonly", false, "save data in an unexported map of strings with accessor functions")
+	fs.BoolVar(&gen.vars.BytesViaString, "bytes-via-string", false, "save data as strings and access them as byte slices (faster to compile)")
+	fs.StringVar(&constPrefix, "const-prefix", "", "generate constants for the file names with this prefix")
+	fs.StringVar(&tree, "tree", "", "generate a variable with this name giving access to the files by path (e.g. Assets.Templates.IndexHTML())")
+	fs.StringVar(&gen.vars.Receiver, "receiver", "", "generate a type with this name with a method per file (e.g. Assets{}.StaticIndexHTML())")
+	fs.StringVar(&naming, "tree-naming", "initialisms", "naming strategy of the identifiers of -tree and -receiver: initialisms (IndexHTML) or plain (IndexHtml)")
+	fs.StringVar(&gen.onCollision, "on-collision", "error", "policy when files have the same key: first, last or error")
+	fs.StringVar(&secretScan, "secret-scan", "", "check the files for secrets (private keys, cloud credentials, .env files) and warn or error")
+	fs.Var(&secretAllow, "secret-allow", "do not check the files matching this pattern for secrets (repeatable)")
+	fs.Var(&gen.only, "only", "only update the files matching a pattern in the existing output file (repeatable)")
+	fs.StringVar(&mirror, "mirror", "", "embed exactly the files of this existing generated file, resolved against the root of the keys (-r)")
+	fs.Var(&gen.routes, "o-for", "write the files matching a pattern to another file (pattern=file, repeatable)")
+	fs.Var(&gen.transforms, "transform", "pipe matching files through a command ([pattern=]command args, repeatable)")
+	fs.StringVar(&gen.eol, "normalize-eol", "", "convert the line endings of the text files (valid UTF-8 without NUL bytes): lf or crlf")
+	fs.BoolVar(&gen.stripBOM, "strip-bom", false, "strip the UTF-8 byte order mark of the text files")
+	fs.StringVar(&gen.transformCache, "transform-cache", "", "directory caching the outputs of the transforms by command and contents")
+	fs.StringVar(&configFile, "c", "", "configuration file")
+	fs.StringVar(&profile, "profile", "", "profile of the configuration file selecting the inputs, compression and output file")
+	fs.Var(&budget, "budget", "maximum total size of the files (e.g. 10MB)")
+	fs.Var(&embedOver, "embed-over", "embed the files larger than this size (e.g. 1MB) with go:embed from a directory next to the output file instead of literals")
+	fs.Var(&gen.maxMem, "max-mem", "maximum size of the contents held in memory (e.g. 512MB), the other files being streamed to the output")
+	fs.BoolVar(&doc, "doc", false, "list the files and their sizes in the package documentation")
+	fs.BoolVar(&comments, "comments", false, "comment each file with its source path, size and SHA-256 hash")
+	fs.Var(&layers, "layer", "add the files of this directory keyed relative to it, overriding those of the previous layers (repeatable)")
+	fs.Var(&goPkgs, "go-pkg", "embed the Go source files of the packages matching this pattern, e.g. ./... (repeatable)")
+	fs.BoolVar(&fromArchive, "from-archive", false, "treat zip and tar archives as directory trees")
+	fs.BoolVar(&gitignore, "gitignore", false, "honour .gitignore files in directories")
+	fs.StringVar(&lockFile, "lock", "", "lockfile pinning the SHA-256 checksums of the files (e.g. bindata.lock)")
+	fs.BoolVar(&updateLock, "update-lock", false, "write the checksums of the files to the lockfile instead of verifying them")
+	fs.BoolVar(&skipEmpty, "skip-empty", false, "skip the empty files, e.g. placeholders like .keep")
+	fs.Var(&gen.filter.MinSize, "min-size", "skip the files of directories smaller than this size (e.g. 1KB)")
+	fs.IntVar(&gen.filter.MaxDepth, "max-depth", 0, "maximum number of directory levels walked in the inputs, 1 for the files of the input directories only (0 for no limit)")
+	fs.Var(&gen.filter.MaxSize, "max-size", "skip the files of directories larger than this size (e.g. 10MB)")
+	fs.DurationVar(&gen.filter.MaxAge, "max-age", 0, "skip the files of directories modified longer ago than this duration (e.g. 720h)")
+	fs.BoolVar(&version, "bundle-version", false, "generate a constant fingerprinting the contents of the files")
+	fs.BoolVar(&hashNames, "hash-names", false, "store files under content-addressed keys")
+	fs.StringVar(&cacheHash, "cache-hash", "sha256", "hash of the contents in content-addressed keys and bundle versions: sha256 or xxhash (faster, not collision resistant)")
+	fs.StringVar(&salt, "obfuscate-keys", "", "store files under hashes of their names salted with this string, so that the names do not appear in binaries")
+	fs.BoolVar(&verbose, "v", false, "report the files embedded, with their sizes and running totals")
+	fs.StringVar(&reportFile, "report", "", "write a JSON summary of the sizes of the files to this file")
+	fs.StringVar(&jsonManifest, "json-manifest", "", "write the names the files are embedded under, by path, as JSON to this file")
+	fs.StringVar(&tsManifest, "ts-manifest", "", "write the paths and names of the files as TypeScript declarations to this file")
+	fs.StringVar(&provenanceFile, "provenance", "", "write the source path, git commit and modification time of each file as JSON to this file")
+	fs.BoolVar(&phases, "progress", false, "report the duration of each phase (walk, read, encode, write)")
+	fs.StringVar(&logFormat, "log", "text", "format of the diagnostics on stderr: text or json (JSON lines)")
+	fs.BoolVar(&gen.strict, "strict", false, "report all the unreadable inputs together")
+	fs.BoolVar(&perDir, "per-dir-output", false, "generate one file per input directory, in that directory's package")
+	args, err := ExpandArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	paths := fs.Args()
+	if profile != "" {
+		if configFile == "" {
+			return fmt.Errorf("-profile requires a configuration file (-c)")
+		}
+		c, err := LoadConfig(configFile)
+		if err != nil {
+			return err
+		}
+		p, err := c.Profile(profile)
+		if err != nil {
+			return err
+		}
+		set := make(map[string]bool)
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		if !set["o"] {
+			out = p.Output
+		}
+		if !set["compress"] && p.Compress != "" {
+			compress = p.Compress
+		}
+		paths = append(paths[:len(paths):len(paths)], p.Inputs...)
+	}
+	switch logFormat {
+	case "text":
+	case "json":
+		gen.log.json = true
+	default:
+		return fmt.Errorf("invalid -log format %q", logFormat)
+	}
+	stdout := out == "-"
+	if stdout {
+		out, gen.quiet = "", true
+		if !gen.log.json {
+			gen.log.w = io.Discard
+		}
+	}
+
+	switch gen.eol {
+	case "", "lf", "crlf":
+	default:
+		return fmt.Errorf("invalid -normalize-eol %q", gen.eol)
+	}
+	if secretScan != "" && secretScan != "warn" && secretScan != "error" {
+		return fmt.Errorf("invalid -secret-scan policy %q", secretScan)
+	}
+	if cacheHashes[cacheHash] == nil {
+		return fmt.Errorf("invalid -cache-hash %q", cacheHash)
+	}
+	switch gen.onCollision {
+	case "first", "last", "error":
+	default:
+		return fmt.Errorf("invalid -on-collision policy %q", gen.onCollision)
+	}
+	switch compress {
+	case "none":
+		compress = ""
+	case "", "gzip", "flate", "auto":
+	default:
+		return fmt.Errorf("invalid -compress codec %q", compress)
+	}
+	if gen.vars.Generated != "" && !strings.HasPrefix(gen.vars.Generated, "//") {
+		gen.vars.Generated = "// " + gen.vars.Generated
+	}
+	switch abs {
+	case "reject", "trim", "keep":
+	default:
+		return fmt.Errorf("invalid -abs policy %q", abs)
+	}
+	switch layout {
+	case "map", "slice", "vars":
+		gen.vars.Slice, gen.vars.PerFile = layout == "slice", layout == "vars"
+	case "blob":
+		// a sorted slice of offsets in a single string constant
+		gen.vars.Slice, gen.vars.AsString = true, true
+	default:
+		return fmt.Errorf("invalid -layout %q", layout)
+	}
+	switch target {
+	case "":
+	case "tinygo":
+		// maps are allocated at init, unlike a slice of strings kept in read-only memory
+		gen.vars.Slice, gen.vars.AsString = !gen.vars.PerFile, true
+	default:
+		return fmt.Errorf("invalid -target %q", target)
+	}
+	perm, times, err := ParseMeta(meta)
+	if err != nil {
+		return err
+	}
+	gen.vars.Meta, gen.vars.Times = meta != "", times
+	gen.vars.AssetError, gen.vars.AssetPanic, gen.vars.Names = false, false, false
+	if accessors != "" {
+		for _, a := range strings.Split(accessors, ",") {
+			switch a {
+			case "error":
+				gen.vars.AssetError = true
+			case "panic":
+				gen.vars.AssetPanic = true
+			case "const":
+				gen.vars.Names = true
+			default:
+				return fmt.Errorf("invalid -accessors kind %q", a)
+			}
+		}
+	}
+	gen.vars.Typed, gen.vars.Decoders = typed != "", nil
+	if typed != "" {
+		for _, d := range strings.Split(typed, ",") {
+			switch d {
+			case "json", "yaml":
+				gen.vars.Decoders = append(gen.vars.Decoders, d)
+			case "none":
+			default:
+				return fmt.Errorf("invalid -typed decoder %q", d)
+			}
+		}
+		slices.Sort(gen.vars.Decoders)
+		gen.vars.Decoders = slices.Compact(gen.vars.Decoders)
+	}
+	switch templates {
+	case "", "text", "html":
+		gen.vars.Templates = templates
+	default:
+		return fmt.Errorf("invalid -templates package %q", templates)
+	}
+	if _, ok := namings[naming]; !ok {
+		return fmt.Errorf("invalid -tree-naming %q", naming)
+	}
+	if gen.columns < 0 {
+		return fmt.Errorf("invalid -columns %d", gen.columns)
+	}
+	if gen.tab == "" || strings.Trim(gen.tab, " \t") != "" {
+		return fmt.Errorf("invalid -tab %q: tabs or spaces only", gen.tab)
+	}
+	if gen.vars.Migrate || gen.vars.Walk {
+		gen.vars.FS = true
+	}
+	if gen.vars.Precompressed {
+		gen.vars.ServeHTTP = true
+	}
+	lv, err := ParseLang(lang)
+	if err != nil {
+		return fmt.Errorf("-lang: %v", err)
+	}
+	gen.vars.Go = lv
+	emitter, emitFlag := emitters[emit], ""
+	if emitter == nil && emit != "go" {
+		return fmt.Errorf("invalid -emit language %q", emit)
+	}
+	if emitter != nil {
+		emitFlag = "-emit " + emit
+	}
+	if customTemplate != "" {
+		emitFlag = "-t"
+	}
+	gen.vars.Salt, gen.vars.SaltLen = salt, obfuscatedLen
+
+	var config Config
+	if configFile != "" {
+		c, err := LoadConfig(configFile)
+		if err != nil {
+			return err
+		}
+		config = *c
+		if running := toolVersion(); running == develVersion && config.Version != "" {
+			gen.log.log(Event{Level: "warning", Kind: "pin", Message: fmt.Sprintf("%s pins bindata %s, which a development build cannot check", configFile, config.Version)})
+		} else if err := checkPin(configFile, config.Version, running); err != nil {
+			return err
+		}
+	}
+	if budget != 0 {
+		config.Budget = budget
+	}
+
+	if err := gen.check(&options{
+		out: out, stdout: stdout, inputs: len(paths) > 0 || len(gen.sources) > 0,
+		mirror: mirror, compress: compress, layout: layout, target: target, emit: emit, emitFlag: emitFlag,
+		spa: spa, tree: tree, accessors: accessors, constPrefix: constPrefix, overrideKey: overrideKey,
+		lockFile: lockFile, reportFile: reportFile, provenanceFile: provenanceFile,
+		jsonManifest: jsonManifest, tsManifest: tsManifest,
+		hashNames: hashNames, perDir: perDir, comments: comments, version: version, doc: doc, updateLock: updateLock,
+		layers: len(layers) > 0, goPkgs: len(goPkgs) > 0, copies: len(copies) > 0, aliases: len(config.Aliases) > 0,
+		groups: len(config.Groups) > 0, embedOver: embedOver, spillOver: spillOver,
+	}); err != nil {
+		return err
+	}
+
+	if len(goPkgs) > 0 {
+		files, err := GoPackageFiles(goPkgs)
+		if err != nil {
+			return err
+		}
+		paths = append(paths[:len(paths):len(paths)], files...)
+	}
+	var mirrored *Mirror
+	if mirror != "" {
+		m, err := LoadMirror(mirror)
+		if err != nil {
+			return fmt.Errorf("-mirror: %v", err)
+		}
+		if paths, err = m.Inputs(prefix); err != nil {
+			return fmt.Errorf("-mirror: %v", err)
+		}
+		mirrored = m
+	}
+	for _, arg := range paths {
+		if IsURL(arg) {
+			continue
+		}
+		path, _, _ := SplitInput(arg)
+		gen.inputs = append(gen.inputs, path)
+	}
+	if header != "" {
+		text, err := readSnippet(header)
+		if err != nil {
+			return err
+		}
+		gen.vars.Header = text + "\n"
+	}
+	if footer != "" {
+		text, err := readSnippet(footer)
+		if err != nil {
+			return err
+		}
+		gen.vars.Footer = "\n" + text
+	}
+	var epoch time.Time
+	if times {
+		if epoch, err = SourceDateEpoch(); err != nil {
+			return err
+		}
+	}
+	if overrideKey != "" {
+		key, err := ParsePublicKey(overrideKey)
+		if err != nil {
+			return fmt.Errorf("-override-key: %v", err)
+		}
+		gen.vars.OverrideKey = key
+	}
+	if customTemplate != "" {
+		t, err := ParseTemplate(customTemplate)
+		if err != nil {
+			return err
+		}
+		emitter = TemplateEmitter(t)
+	}
+	if gitignore {
+		gen.ignoreFiles = append(gen.ignoreFiles, ".gitignore")
+	}
+
+	rep := newReporter(gen.log, verbose, phases, gen.onProgress)
+	if verbose || phases {
+		gen.onProgress = rep.add
+	}
+
+	r := &runState{
+		rep: rep, config: config, mirrored: mirrored, emitter: emitter, perm: perm, epoch: epoch,
+		abs: abs, fromArchive: fromArchive, layers: layers, skipEmpty: skipEmpty,
+		secretScan: secretScan, secretAllow: secretAllow, lockFile: lockFile, updateLock: updateLock,
+		compress: compress, layout: layout, spa: spa, tree: tree, naming: naming, templates: templates,
+		salt: salt, hashNames: hashNames, cacheHash: cacheHash, constPrefix: constPrefix,
+		comments: comments, doc: doc, version: version, embedOver: embedOver, spillOver: spillOver,
+		emit: emit, customTemplate: customTemplate, copies: copies, force: force, forceWrite: forceWrite,
+		checksum: checksum, verbose: verbose, reportFile: reportFile, jsonManifest: jsonManifest,
+		tsManifest: tsManifest, provenanceFile: provenanceFile,
+	}
+	if !perDir {
+		return gen.generate(r, out, prefix, paths)
+	}
+	// generate one file per input directory, in the package of the directory
+	explicitPkg := false
+	fs.Visit(func(f *flag.Flag) {
+		explicitPkg = explicitPkg || f.Name == "p"
+	})
+	if out == "" {
+		out = "bindata.go"
+	}
+	for _, dir := range paths {
+		fi, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return fmt.Errorf("%s: not a directory", dir)
+		}
+		if !explicitPkg {
+			if gen.vars.Pkg, err = PackageName(dir); err != nil {
+				return err
+			}
+		}
+		if err := gen.generate(r, filepath.Join(dir, filepath.Base(out)), dir, []string{dir}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A runState holds the settings of the generations of a command line which
+// are not template variables, once resolved from its flags.
+type runState struct {
+	rep      *reporter
+	config   Config
+	mirrored *Mirror // files checked against those of -mirror, if not nil
+	emitter  func(w io.Writer, generated, name string, assets map[string]*Asset, keys []string) error
+	perm     string    // -meta permissions
+	epoch    time.Time // SOURCE_DATE_EPOCH, if set
+
+	// inputs
+	abs                    string
+	fromArchive, skipEmpty bool
+	layers                 Paths
+	secretScan             string
+	secretAllow            Patterns
+	lockFile               string
+	updateLock             bool
+
+	// keys and contents
+	compress, layout, spa, tree, naming, templates string
+	salt, cacheHash, constPrefix                   string
+	hashNames, comments, doc, version              bool
+	embedOver, spillOver                           Size
+
+	// outputs
+	emit, customTemplate                                 string
+	copies                                               Paths
+	force, forceWrite, checksum, verbose                 bool
+	reportFile, jsonManifest, tsManifest, provenanceFile string
+}
+
+// generate embeds the files of paths, their keys relative to prefix, in out
+// (stdout if empty) along with the other outputs of r.
+func (gen *generator) generate(r *runState, out, prefix string, paths []string) error {
+	embedDir := ""
+	if r.embedOver > 0 {
+		embedDir = filepath.Join(filepath.Dir(out), EmbedDir(out))
+	}
+	if err := gen.collect(r, out, embedDir, prefix, paths); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(gen.assets))
+	for key := range gen.assets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	slashed := toSlash(keys)
+	gen.vars.Migrations = nil
+	if gen.vars.Migrate {
+		migrations, err := Migrations(slashed)
+		if err != nil {
+			return err
+		}
+		gen.vars.Migrations = migrations
+	}
+	gen.vars.Tree = nil
+	if r.tree != "" {
+		t, err := NewTree(r.tree, slashed, r.naming)
+		if err != nil {
+			return fmt.Errorf("-tree: %v", err)
+		}
+		gen.vars.Tree = t
+	}
+	gen.vars.Methods = nil
+	if gen.vars.Receiver != "" {
+		methods, err := ReceiverMethods(slashed, r.naming)
+		if err != nil {
+			return fmt.Errorf("-receiver: %v", err)
+		}
+		gen.vars.Methods = methods
+	}
+	gen.vars.Idents = nil
+	if gen.vars.PerFile {
+		files, err := ReceiverMethods(slashed, r.naming)
+		if err != nil {
+			return fmt.Errorf("-layout vars: %v", err)
+		}
+		gen.vars.Idents = make(map[string]string, len(files))
+		for _, f := range files {
+			gen.vars.Idents[f.Key] = f.Name
+		}
+	}
+	gen.vars.TemplateFiles = nil
+	if r.templates != "" {
+		for _, key := range keys {
+			if IsTemplate(key) {
+				gen.vars.TemplateFiles = append(gen.vars.TemplateFiles, filepath.ToSlash(key))
+			}
+		}
+	}
+
+	if err := CheckBudgets(gen.assets, r.config.Budget, r.config.Budgets); err != nil {
+		return err
+	}
+	// files of groups and routed files are written to separate files
+	groups, err := AssignGroups(gen.assets, r.config.Groups)
+	if err != nil {
+		return err
+	}
+	routed := gen.routes.Assign(gen.assets)
+	split := make(map[string]*group) // indexed by key
+	if len(groups) > 0 || len(routed) > 0 {
+		byFile := make(map[string]*group)
+		for key, tag := range groups {
+			file := groupFile(out, tag)
+			if byFile[file] == nil {
+				byFile[file] = &group{File: file, Tag: tag, Desc: "of group " + tag}
+			}
+			split[key] = byFile[file]
+		}
+		for key, r := range routed {
+			if tag, ok := groups[key]; ok {
+				return fmt.Errorf("file %q belongs to group %s and matches -o-for %s", key, tag, r.Pattern)
+			}
+			if byFile[r.File] == nil {
+				byFile[r.File] = &group{File: r.File, Desc: "matching " + gen.routes.Patterns(r.File)}
+			}
+			split[key] = byFile[r.File]
+		}
+		keys = slices.DeleteFunc(keys, func(key string) bool {
+			return split[key] != nil
+		})
+	}
+
+	gen.vars.Imports = nil
+	gen.vars.Var, gen.vars.Unexported = gen.vars.Map, unexported(gen.vars.Map)
+	if gen.vars.ReadOnly || gen.vars.BytesViaString {
+		gen.vars.AsString = true
+		gen.vars.Var = unexported(gen.vars.Map) + "Files"
+	}
+	if gen.vars.ReadOnly {
+		gen.vars.Imports = append(gen.vars.Imports, "unsafe")
+	}
+	gen.vars.Hashed, gen.vars.HashedOrder = nil, nil
+	if r.hashNames && len(gen.assets) > 0 {
+		gen.vars.Imports = append(gen.vars.Imports, "strings")
+		gen.vars.Hashed = make(map[string]string)
+		for key, a := range gen.assets {
+			gen.vars.Hashed[key] = hashedName(key, cacheHashes[r.cacheHash](a))
+		}
+		gen.vars.HashedOrder = longestFirst(gen.vars.Hashed)
+	}
+
+	gen.vars.SPA = ""
+	if r.spa != "" {
+		a, ok := gen.assets[filepath.FromSlash(r.spa)]
+		if !ok || split[a.Name] != nil {
+			return fmt.Errorf("-spa: no file %q", r.spa)
+		}
+		gen.vars.SPA = a.Name
+		if hashed, ok := gen.vars.Hashed[a.Name]; ok {
+			gen.vars.SPA = hashed
+		}
+		gen.vars.Imports = append(gen.vars.Imports, "net/http", "path", "strings", "time")
+		if !gen.vars.AsString || r.compress != "" {
+			gen.vars.Imports = append(gen.vars.Imports, "bytes")
+		}
+	}
+
+	gen.vars.Errors = r.compress != "" || gen.vars.Writer || gen.vars.Patch || gen.vars.AssetError || gen.vars.AssetPanic || gen.vars.Typed
+	gen.addImports(r)
+
+	gen.vars.Files = make(map[string]fmt.Formatter)
+	gen.vars.Compress, gen.vars.Codecs = r.compress, make(map[string]string)
+	gen.vars.Comments = make(map[string]string)
+	sizes := make(map[string]int)
+	var reported []FileReport
+	var blob bytes.Buffer
+	offsets := make(map[string]int)
+	gen.vars.Blob, gen.vars.Offsets = nil, make(map[string][2]int)
+	gen.vars.Modes, gen.vars.ModTimes = make(map[string]os.FileMode), make(map[string]int64)
+	gen.vars.Gzip = make(map[string]fmt.Formatter)
+	gen.vars.FileSizes, gen.vars.TotalSize = make(map[string][2]int), 0
+	gen.vars.Infos = make(map[string]assetInfo)
+	gen.vars.EmbedOver, gen.vars.EmbedDir, gen.vars.Embedded = r.embedOver, filepath.Base(embedDir), make(map[string]string)
+	embedded := make(map[string][]byte) // contents of the files embedded with go:embed by name
+	gen.vars.SpillOver, gen.vars.Spilled = r.spillOver, make(map[string]string)
+	var streamed []io.WriterTo
+	for _, key := range keys {
+		a := gen.assets[key]
+		data, codec, err := Compress(r.compress, key, a.Data)
+		if err != nil {
+			return err
+		}
+		stored := len(data)
+		if a.Lazy {
+			stored = a.Len()
+		}
+		if hashed, ok := gen.vars.Hashed[key]; ok {
+			key = hashed
+		}
+		if r.salt != "" {
+			key = ObfuscatedKey(r.salt, filepath.ToSlash(key))
+		}
+		gen.vars.Files[key] = gen.formatter(data)
+		if r.layout == "blob" {
+			gen.vars.Files[key] = nil
+			offset, ok := offsets[string(data)] // identical files share their data
+			if !ok {
+				offset = blob.Len()
+				offsets[string(data)] = offset
+				blob.Write(data)
+			}
+			gen.vars.Offsets[key] = [2]int{offset, len(data)}
+		}
+		if codec != "" {
+			gen.vars.Codecs[key] = codec
+			if r.spillOver > 0 && Size(a.Len()) > r.spillOver {
+				sum := a.SHA256()
+				gen.vars.Spilled[key] = hex.EncodeToString(sum[:])
+			}
+		}
+		if gen.vars.Precompressed {
+			gz, codec, err := Compress("gzip", a.Name, a.Data) // key may be hashed by now
+			if err != nil {
+				return err
+			}
+			if codec != "" {
+				gen.vars.Gzip[key] = gen.formatter(gz)
+			}
+		}
+		if r.comments {
+			gen.vars.Comments[key] = a.Comment(codec, len(data))
+		}
+		if r.embedOver > 0 && Size(len(data)) > r.embedOver {
+			name := EmbeddedName(data)
+			gen.vars.Files[key], gen.vars.Embedded[key], embedded[name] = nil, name, data
+		}
+		if gen.maxMem > 0 && a.Len() > 0 {
+			gen.vars.Files[key] = streamRef(len(streamed))
+			streamed = append(streamed, streamFormatter{a, gen.readFormatter})
+		}
+		sizes[key] = a.Len()
+		gen.vars.FileSizes[key] = [2]int{a.Len(), stored}
+		gen.vars.TotalSize += a.Len()
+		reported = append(reported, FileReport{filepath.ToSlash(key), Size(a.Len()), Size(stored)})
+		if mode := metaMode(r.perm, a.Mode); gen.vars.Meta && mode != defaultMode {
+			gen.vars.Modes[key] = mode
+		}
+		if t := metaTime(a.Time, r.epoch); gen.vars.Times && t != 0 {
+			gen.vars.ModTimes[key] = t
+		}
+		if gen.vars.Stat {
+			info := newAssetInfo(a, r.perm, gen.vars.Times, r.epoch)
+			if gen.vars.GitMeta && a.Path != "" {
+				info.Commit = gitLastCommit(a.Path)
+			}
+			gen.vars.Infos[key] = info
+		}
+	}
+	if r.layout == "blob" {
+		gen.vars.Blob = StringFormatter{Reader: bytes.NewReader(blob.Bytes()), Columns: gen.columns, Tab: gen.tab, Uppercase: gen.uppercase}
+	}
+	if len(embedded) > 0 {
+		gen.vars.Imports = append(gen.vars.Imports, "embed")
+	}
+	if err := gen.setAliases(r); err != nil {
+		return err
+	}
+	gen.vars.Doc = nil
+	if r.doc {
+		gen.vars.Doc = docLines(sizes)
+	}
+	gen.vars.Consts, gen.vars.ConstWidth = nil, 0
+	if r.constPrefix != "" {
+		if gen.vars.Consts, err = constants(r.constPrefix, keys); err != nil {
+			return err
+		}
+		for i, c := range gen.vars.Consts {
+			if len(c.Name) > gen.vars.ConstWidth {
+				gen.vars.ConstWidth = len(c.Name)
+			}
+			if hashed, ok := gen.vars.Hashed[c.Value]; ok {
+				gen.vars.Consts[i].Value = hashed
+			}
+		}
+	}
+
+	gen.vars.Version = ""
+	if r.version {
+		gen.vars.Version = bundleVersion(gen.assets, cacheHashes[r.cacheHash])
+	}
+
+	slices.Sort(gen.vars.Imports)
+	gen.vars.Imports = slices.Compact(gen.vars.Imports)
+	r.rep.done("encode")
+	if !r.force && gen.vars.Generated != "" {
+		// never clobber a hand-written file, e.g. after a typo in -o
+		targets := append([]string{out}, r.copies...)
+		for _, g := range split {
+			targets = append(targets, g.File)
+		}
+		for _, file := range targets {
+			if file == "" {
+				continue
+			}
+			if err := CheckOverwrite(file, gen.vars.Generated, r.emit == "json"); err != nil {
+				return err
+			}
+		}
+	}
+	var write func(w io.Writer) error
+	if gen.maxMem > 0 {
+		// the output is streamed, rendered again for each copy
+		write = func(w io.Writer) error {
+			if !r.checksum {
+				return gen.tmpl.Execute(&spliceWriter{w, streamed}, gen.vars)
+			}
+			h := sha256.New()
+			if err := gen.tmpl.Execute(&spliceWriter{io.MultiWriter(w, h), streamed}, gen.vars); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(w, "\n%s%x\n", checksumPrefix, h.Sum(nil))
+			return err
+		}
+	} else {
+		// the output is rendered once so that all the copies are identical
+		var buf bytes.Buffer
+		if r.emitter != nil {
+			if err := r.emitter(&buf, gen.vars.Generated, gen.vars.Map, gen.assets, keys); err != nil {
+				return err
+			}
+			// without it, the output could not be overwritten by the next run
+			if r.customTemplate != "" && gen.vars.Generated != "" && !bytes.Contains(buf.Bytes(), []byte(gen.vars.Generated)) {
+				return fmt.Errorf("%s does not print .Generated, which marks the output as generated so that it can be overwritten (-generated \"\" for none)", r.customTemplate)
+			}
+		} else if err := gen.tmpl.Execute(&buf, gen.vars); err != nil {
+			return err
+		}
+		data := buf.Bytes()
+		if r.checksum {
+			data = AppendChecksum(data)
+		}
+		write = func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		}
+	}
+	// unchanged files are not written again, to preserve their modification time
+	update := func(path string, write func(io.Writer) error) error {
+		if r.forceWrite {
+			return WriteFile(path, write)
+		}
+		written, err := UpdateFile(path, write)
+		if err == nil && !written && r.verbose {
+			gen.log.log(Event{Level: "info", Kind: "skip", Message: path + " unchanged, not written", Path: path})
+		}
+		return err
+	}
+	if embedDir != "" {
+		if err := WriteEmbedded(embedDir, embedded, update); err != nil {
+			return err
+		}
+	}
+	if out == "" {
+		if err := write(os.Stdout); err != nil {
+			return err
+		}
+	} else if err := update(out, write); err != nil {
+		return err
+	}
+	for _, dest := range r.copies {
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := update(dest, write); err != nil {
+			return err
+		}
+	}
+
+	groupReports, err := gen.writeGroups(r, split, update)
+	if err != nil {
+		return err
+	}
+	reported = append(reported, groupReports...)
+	r.rep.done("write")
+
+	return gen.writeReports(r, reported, update)
+}
+
+// addImports adds the packages imported by the features of the generated
+// code to its imports.
+func (gen *generator) addImports(r *runState) {
+	if gen.vars.FS {
+		gen.vars.Imports = append(gen.vars.Imports, "bytes", "errors", "io", "io/fs", "path", "sort", "strings", "time")
+	}
+	if gen.vars.Iter {
+		gen.vars.Imports = append(gen.vars.Imports, "iter")
+		if !gen.vars.Slice {
+			gen.vars.Imports = append(gen.vars.Imports, "maps", "slices")
+		}
+	}
+	if gen.vars.Localized != "" {
+		gen.vars.Imports = append(gen.vars.Imports, "path", "strings")
+	}
+	if gen.vars.Errors {
+		gen.vars.Imports = append(gen.vars.Imports, "os")
+	}
+	if gen.vars.Register != "" {
+		gen.vars.Imports = append(gen.vars.Imports, runtimePkg)
+	}
+	if r.salt != "" {
+		gen.vars.Imports = append(gen.vars.Imports, "crypto/sha256", "encoding/hex")
+	}
+	if gen.vars.ServeHTTP {
+		gen.vars.Imports = append(gen.vars.Imports, "net/http", "time")
+		if gen.vars.AsString && r.compress == "" {
+			gen.vars.Imports = append(gen.vars.Imports, "strings")
+		} else {
+			gen.vars.Imports = append(gen.vars.Imports, "bytes")
+		}
+		if gen.vars.Precompressed {
+			gen.vars.Imports = append(gen.vars.Imports, "mime", "path", "strconv", "strings")
+		}
+	}
+	if gen.vars.Writer {
+		gen.vars.Imports = append(gen.vars.Imports, "io")
+		if gen.vars.AsString {
+			gen.vars.Imports = append(gen.vars.Imports, "strings")
+		} else {
+			gen.vars.Imports = append(gen.vars.Imports, "bytes")
+		}
+	}
+	if gen.vars.Stat {
+		gen.vars.Imports = append(gen.vars.Imports, "io/fs", "time")
+	}
+	if gen.vars.Patch {
+		gen.vars.Imports = append(gen.vars.Imports, "crypto/sha256", "encoding/binary", "errors")
+	}
+	if gen.vars.Meta {
+		gen.vars.Imports = append(gen.vars.Imports, "errors", "io/fs", "os", "path/filepath")
+		if gen.vars.Times {
+			gen.vars.Imports = append(gen.vars.Imports, "time")
+		}
+	}
+	if r.templates != "" {
+		gen.vars.Imports = append(gen.vars.Imports, "sync", r.templates+"/template")
+	}
+	if gen.vars.Override != "" {
+		gen.vars.Imports = append(gen.vars.Imports, "archive/zip", "io/fs", "os", "strings", "sync")
+		if gen.vars.OverrideKey != nil {
+			gen.vars.Imports = append(gen.vars.Imports, "bytes", "crypto/ed25519")
+		}
+	}
+	if gen.vars.Typed {
+		gen.vars.Imports = append(gen.vars.Imports, "reflect", "sync")
+		for _, d := range gen.vars.Decoders {
+			gen.vars.Imports = append(gen.vars.Imports, map[string]string{"json": "encoding/json", "yaml": "gopkg.in/yaml.v3"}[d])
+		}
+	}
+	if r.compress != "" {
+		gen.vars.Imports = append(gen.vars.Imports, "io")
+		if gen.vars.Go < 16 {
+			gen.vars.Imports[len(gen.vars.Imports)-1] = "io/ioutil"
+		}
+		if r.compress != "flate" {
+			gen.vars.Imports = append(gen.vars.Imports, "compress/gzip")
+		}
+		if r.compress != "gzip" {
+			gen.vars.Imports = append(gen.vars.Imports, "compress/flate")
+		}
+		if gen.vars.AsString {
+			gen.vars.Imports = append(gen.vars.Imports, "strings")
+		} else {
+			gen.vars.Imports = append(gen.vars.Imports, "bytes")
+		}
+		if gen.vars.Preload {
+			gen.vars.Imports = append(gen.vars.Imports, "context", "sync")
+		}
+		if r.spillOver > 0 {
+			gen.vars.Imports = append(gen.vars.Imports, "bytes", "crypto/sha256", "encoding/hex", "os", "path/filepath", "sync")
+		}
+	}
+}
+
+// setAliases adds the aliases of the configuration to the files of the
+// generated map, with the metadata of the files they alias.
+func (gen *generator) setAliases(r *runState) error {
+	gen.vars.Aliases = make(map[string]string)
+	for _, alias := range slices.Sorted(maps.Keys(r.config.Aliases)) {
+		key := filepath.FromSlash(r.config.Aliases[alias])
+		if _, ok := gen.vars.Files[key]; !ok {
+			return fmt.Errorf("alias %q: no file %q", alias, r.config.Aliases[alias])
+		}
+		if _, ok := gen.vars.Files[filepath.FromSlash(alias)]; ok {
+			return fmt.Errorf("alias %q: already the key of a file", alias)
+		}
+		gen.vars.Aliases[alias] = key
+		// the aliases have the metadata of their files
+		if codec, ok := gen.vars.Codecs[key]; ok {
+			gen.vars.Codecs[alias] = codec
+		}
+		if sum, ok := gen.vars.Spilled[key]; ok {
+			gen.vars.Spilled[alias] = sum
+		}
+		if mode, ok := gen.vars.Modes[key]; ok {
+			gen.vars.Modes[alias] = mode
+		}
+		if t, ok := gen.vars.ModTimes[key]; ok {
+			gen.vars.ModTimes[alias] = t
+		}
+		if info, ok := gen.vars.Infos[key]; ok {
+			gen.vars.Infos[alias] = info
+		}
+		gen.vars.FileSizes[alias] = gen.vars.FileSizes[key]
+	}
+	return nil
+}
+
+// collect adds the files of paths to the assets, their keys relative to
+// prefix, and filters them: the outputs of r (writing out and the files
+// embedded in embedDir) are never embedded.
+func (gen *generator) collect(r *runState, out, embedDir, prefix string, paths []string) error {
+	r.rep.reset()
+	gen.memUsed = 0
+	gen.assets = make(map[string]*Asset)
+	gen.inputErrors = nil
+	for _, layer := range r.layers {
+		// the files of a layer override those of the layers below
+		below := gen.assets
+		gen.assets = make(map[string]*Asset)
+		if err := gen.addInput(layer, layer, r.abs, r.fromArchive); err != nil {
+			return err
+		}
+		maps.Copy(below, gen.assets)
+		gen.assets = below
+	}
+	for _, path := range paths {
+		if err := gen.addInput(path, prefix, r.abs, r.fromArchive); err != nil {
+			return err
+		}
+	}
+	for _, src := range gen.sources {
+		if err := gen.addSource(src); err != nil {
+			return err
+		}
+	}
+	if len(gen.inputErrors) > 0 {
+		return gen.inputErrors
+	}
+	r.rep.collected()
+	if r.mirrored != nil {
+		if err := r.mirrored.Check(gen.assets); err != nil {
+			return fmt.Errorf("-mirror: %v", err)
+		}
+	}
+	if gen.vars.Migrate {
+		maps.DeleteFunc(gen.assets, func(key string, _ *Asset) bool { return !IsMigration(key) })
+	}
+	if r.skipEmpty {
+		maps.DeleteFunc(gen.assets, func(_ string, a *Asset) bool { return a.Len() == 0 })
+	}
+	if out != "" || len(r.copies) > 0 {
+		// never embed a previous version of the output files
+		outputs := append([]string{out}, r.copies...)
+		for tag := range r.config.Groups {
+			outputs = append(outputs, groupFile(out, tag))
+		}
+		for _, r := range gen.routes {
+			outputs = append(outputs, r.File)
+		}
+		for _, key := range slices.Sorted(maps.Keys(gen.assets)) {
+			a := gen.assets[key]
+			if a.Path == "" || !slices.ContainsFunc(outputs, func(out string) bool { return SameFile(a.Path, out) }) &&
+				(embedDir == "" || !SameFile(filepath.Dir(a.Path), embedDir)) {
+				continue
+			}
+			if gen.strict {
+				return fmt.Errorf("input %s is an output file", a.Path)
+			}
+			gen.log.log(Event{Level: "warning", Kind: "skip", Message: "skipping output file " + a.Path, Key: key, Path: a.Path})
+			delete(gen.assets, key)
+		}
+	}
+	if r.secretScan != "" {
+		var secrets []string
+		for _, key := range slices.Sorted(maps.Keys(gen.assets)) {
+			a := gen.assets[key]
+			if len(r.secretAllow) > 0 && r.secretAllow.Match(filepath.ToSlash(key)) {
+				continue
+			}
+			data, err := a.Data, error(nil)
+			if a.Lazy {
+				// read one file at a time, not to exceed -max-mem
+				if data, err = os.ReadFile(a.Path); err != nil {
+					return err
+				}
+			}
+			found := ScanSecrets(filepath.ToSlash(key), data)
+			if len(found) == 0 {
+				continue
+			}
+			msg := fmt.Sprintf("%s: possible %s", filepath.ToSlash(key), strings.Join(found, ", "))
+			if r.secretScan == "error" {
+				secrets = append(secrets, msg)
+				continue
+			}
+			gen.log.log(Event{Level: "warning", Kind: "secret", Message: msg, Key: key, Path: a.Path})
+		}
+		if len(secrets) > 0 {
+			return fmt.Errorf("secrets found (see -secret-allow):\n\t%s", strings.Join(secrets, "\n\t"))
+		}
+	}
+	if r.lockFile != "" {
+		// the lockfile is not a file to embed, even among the inputs
+		maps.DeleteFunc(gen.assets, func(_ string, a *Asset) bool { return a.Path != "" && SameFile(a.Path, r.lockFile) })
+	}
+	if r.comments || gen.vars.Stat || r.lockFile != "" || r.spillOver > 0 || r.cacheHash == "sha256" && (r.hashNames || r.version) {
+		hashAssets(gen.assets, func(a *Asset) { a.SHA256() })
+	}
+	if r.cacheHash == "xxhash" && (r.hashNames || r.version) {
+		hashAssets(gen.assets, func(a *Asset) { a.XXHash() })
+	}
+	if r.lockFile != "" {
+		cur := NewLock(gen.assets)
+		if r.updateLock {
+			if err := WriteFile(r.lockFile, cur.Write); err != nil {
+				return err
+			}
+		} else {
+			lock, err := LoadLock(r.lockFile)
+			if err != nil {
+				return err
+			}
+			if diffs := lock.Diff(cur, len(gen.only) > 0); len(diffs) > 0 {
+				return fmt.Errorf("%d file(s) differ from %s (use -update-lock):\n\t%s", len(diffs), r.lockFile, strings.Join(diffs, "\n\t"))
+			}
+		}
+	}
+	if len(gen.only) > 0 {
+		// keep the other files of the existing output file
+		prev, err := ParseGenerated(out)
+		if err != nil {
+			return fmt.Errorf("-only: %v", err)
+		}
+		for _, key := range prev.Keys() {
+			if gen.only.Match(key) {
+				continue
+			}
+			data, err := prev.Read(key)
+			if err != nil {
+				return err
+			}
+			gen.assets[filepath.FromSlash(key)] = &Asset{Name: filepath.FromSlash(key), Data: data, Mode: defaultMode}
+		}
+	}
+	return nil
+}
+
+// writeGroups writes the files of the groups and routed files, split from
+// the output file by key, and returns their reports.
+func (gen *generator) writeGroups(r *runState, split map[string]*group, update func(path string, write func(io.Writer) error) error) ([]FileReport, error) {
+	var reports []FileReport
+	var files []*group
+	for _, key := range slices.Sorted(maps.Keys(split)) {
+		g := split[key]
+		if g.Files == nil {
+			g.Pkg, g.Map, g.Var, g.Go = gen.vars.Pkg, gen.vars.Map, gen.vars.Var, gen.vars.Go
+			g.Header, g.Footer, g.Generated = gen.vars.Header, gen.vars.Footer, gen.vars.Generated
+			g.Files, g.Codecs = make(map[string]fmt.Formatter), make(map[string]string)
+			g.Modes, g.ModTimes = make(map[string]os.FileMode), make(map[string]int64)
+			files = append(files, g)
+		}
+		data, codec, err := Compress(r.compress, key, gen.assets[key].Data)
+		if err != nil {
+			return nil, err
+		}
+		g.Files[key] = gen.formatter(data)
+		if codec != "" {
+			g.Codecs[key] = codec
+		}
+		reports = append(reports, FileReport{filepath.ToSlash(key), Size(len(gen.assets[key].Data)), Size(len(data))})
+		if mode := metaMode(r.perm, gen.assets[key].Mode); gen.vars.Meta && mode != defaultMode {
+			g.Modes[key] = mode
+		}
+		if t := metaTime(gen.assets[key].Time, r.epoch); gen.vars.Times && t != 0 {
+			g.ModTimes[key] = t
+		}
+	}
+	for _, g := range files {
+		if err := update(g.File, func(w io.Writer) error {
+			var buf bytes.Buffer
+			if err := gen.groupTmpl.Execute(&buf, g); err != nil {
+				return err
+			}
+			data := buf.Bytes()
+			if r.checksum {
+				data = AppendChecksum(data)
+			}
+			_, err := w.Write(data)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return reports, nil
+}
+
+// writeReports writes the report of the embedded files, the asset manifests
+// and the provenance of the generation, as requested by r.
+func (gen *generator) writeReports(r *runState, reported []FileReport, update func(path string, write func(io.Writer) error) error) error {
+	if r.verbose || r.reportFile != "" {
+		report := NewReport(reported)
+		if r.verbose {
+			gen.log.log(Event{Level: "info", Kind: "report", Message: report.String(), Size: int(report.Size), Files: report.Files})
+		}
+		if r.reportFile != "" {
+			if err := WriteFile(r.reportFile, report.WriteJSON); err != nil {
+				return err
+			}
+		}
+	}
+	if r.jsonManifest != "" || r.tsManifest != "" {
+		m := NewAssetMap(gen.assets, gen.vars.Hashed)
+		if r.jsonManifest != "" {
+			if err := update(r.jsonManifest, m.WriteJSON); err != nil {
+				return err
+			}
+		}
+		if r.tsManifest != "" {
+			if err := update(r.tsManifest, func(w io.Writer) error {
+				return m.WriteTS(w, gen.vars.Generated, gen.vars.Unexported)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	if r.provenanceFile != "" {
+		p, err := NewProvenance(gen.assets, gen.vars.Hashed)
+		if err != nil {
+			return err
+		}
+		return WriteFile(r.provenanceFile, p.WriteJSON)
+	}
+	return nil
+}
+
+// formatter returns the formatter of data for the storage type of the map.
+func (gen *generator) formatter(data []byte) fmt.Formatter {
+	return gen.readFormatter(bytes.NewReader(data))
+}
+
+// readFormatter returns the formatter of the bytes read from r for the
+// storage type of the map, laid out as set by the command line.
+func (gen *generator) readFormatter(r io.Reader) formatter {
+	indent := "\t"
+	if gen.vars.PerFile {
+		indent = "" // the value of a variable declared at the top level
+	}
+	if gen.vars.AsString {
+		return StringFormatter{Reader: r, Columns: gen.columns, Indent: indent, Tab: gen.tab, Uppercase: gen.uppercase}
+	}
+	return ByteSliceFormatter{Reader: r, Columns: gen.columns, Indent: indent, Tab: gen.tab, Uppercase: gen.uppercase}
+}
+
+// toSlash returns the keys with slashes as separators.
+func toSlash(keys []string) []string {
+	slashed := make([]string, len(keys))
+	for i, key := range keys {
+		slashed[i] = filepath.ToSlash(key)
+	}
+	return slashed
+}
+
+// readSnippet returns the contents of a header or footer file,
+// ending with exactly one newline.
+func readSnippet(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\n") + "\n", nil
+}
+
+// docLines returns the lines of the package documentation listing
+// the files with their sizes, sorted by key.
+func docLines(sizes map[string]int) []string {
+	width, sizeWidth := 0, 0
+	for key, size := range sizes {
+		width = max(width, len(commentSafe(key)))
+		sizeWidth = max(sizeWidth, len(fmt.Sprint(size)))
+	}
+	lines := make([]string, 0, len(sizes))
+	for _, key := range slices.Sorted(maps.Keys(sizes)) {
+		lines = append(lines, fmt.Sprintf("%-*s  %*d bytes", width, commentSafe(key), sizeWidth, sizes[key]))
+	}
+	return lines
+}
+
+// IsTemplate reports whether the file with the given name is a Go template,
+// judging by its extension (.tmpl or .gotmpl).
+func IsTemplate(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".tmpl" || ext == ".gotmpl"
+}
+
+// SplitInput splits a command line input of the form "path:prefix" into the path
+// of the files and the virtual prefix of their keys. Inputs naming existing files
+// are not split.
+func SplitInput(arg string) (path, virtual string, ok bool) {
+	i := strings.LastIndex(arg, ":")
+	if i < len(filepath.VolumeName(arg)) {
+		return arg, "", false
+	}
+	if _, err := os.Stat(arg); err == nil {
+		return arg, "", false
+	}
+	return arg[:i], arg[i+1:], true
+}
+
+// addInput adds the files of a command line input to the assets: a URL,
+// a path with a virtual prefix, an archive (if fromArchive is set) or a file
+// or directory, its keys relative to prefix subject to the abs policy.
+func (gen *generator) addInput(path, prefix, abs string, fromArchive bool) error {
+	if IsURL(path) {
+		return gen.AddURL(path)
+	}
+	if p, virtual, ok := SplitInput(path); ok {
+		return gen.AddPathAs(p, virtual)
+	}
+	if fromArchive && IsArchive(path) {
+		return gen.inputError(gen.AddArchive(path))
+	}
+	if escapes(path, prefix) {
+		root, virtual, err := keyRoot(path, prefix, abs)
+		if err != nil {
+			return err
+		}
+		return gen.addPath(path, root, virtual, nil, nil)
+	}
+	return gen.AddPath(path, prefix)
+}
+
+// AddPath adds files to the assets recursively.
+// Files listed in ignore files within directories are skipped.
+func (gen *generator) AddPath(path, prefix string) error {
+	return gen.addPath(path, prefix, "", nil, nil)
+}
+
+// AddPathAs adds files to the assets recursively, keyed by their path relative
+// to path (or their base name if path is a file) under the virtual prefix.
+func (gen *generator) AddPathAs(path, virtual string) error {
+	prefix := path
+	if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+		prefix = filepath.Dir(path)
+	}
+	return gen.addPath(path, prefix, filepath.FromSlash(virtual), nil, nil)
+}
+
+// addPath adds files to the assets recursively, skipping ignored files.
+// The keys are the paths relative to prefix, under the virtual prefix.
+// The ancestors are the directories walked down to path, to stop at the
+// cycles made by symbolic links or bind mounts and at the maximum depth.
+func (gen *generator) addPath(path, prefix, virtual string, ignore IgnoreList, ancestors []os.FileInfo) error {
+	if err := gen.ctx.Err(); err != nil {
+		return err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return gen.inputError(err)
+	}
+	if ignore.Ignored(path, fi.IsDir()) {
+		return nil
+	}
+	if fi.IsDir() {
+		if gen.filter.MaxDepth > 0 && len(ancestors) >= gen.filter.MaxDepth {
+			return nil
+		}
+		for _, a := range ancestors {
+			if os.SameFile(a, fi) {
+				if gen.strict {
+					return fmt.Errorf("directory cycle: %s is an ancestor of itself", path)
+				}
+				gen.log.log(Event{Level: "warning", Kind: "skip", Message: "skipping directory cycle at " + path, Path: path})
+				return nil
+			}
+		}
+		ancestors = append(ancestors[:len(ancestors):len(ancestors)], fi)
+		dir, err := os.Open(path)
+		if err != nil {
+			return gen.inputError(err)
+		}
+		files, err := dir.Readdir(0)
+		dir.Close()
+		if err != nil {
+			return gen.inputError(err)
+		}
+		if ignore, err = ignore.LoadIgnore(path, gen.ignoreFiles); err != nil {
+			return gen.inputError(err)
+		}
+		now := time.Now()
+		for _, file := range files {
+			if isIgnoreFile(file.Name()) || gen.filter.Excluded(file, now) {
+				continue
+			}
+			if err := gen.addPath(filepath.Join(path, file.Name()), prefix, virtual, ignore, ancestors); err != nil {
+				return err
+			}
+		}
+	} else {
+		name, err := filepath.Rel(prefix, path)
+		if err != nil {
+			return err
+		}
+		return gen.inputError(gen.addSource(fileSource{filepath.Join(virtual, name), path}))
+	}
+	return nil
+}
@@ -0,0 +1,1601 @@
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testdata is the absolute path to the directory containing test datafiles.
+var testdata string
+
+// init initializes the testdata variable.
+func init() {
+	pwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	testdata = filepath.Join(pwd, "testdata")
+}
+
+// runGenerate runs a generation with the arguments of a command line.
+func runGenerate(args []string) error {
+	return Generate(context.Background(), args, nil)
+}
+
+// runTest compares the output generated by the command to a reference.
+func runTest(t *testing.T, ref string, args ...string) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// redirect stdout, restore when we are done
+	defer func(orig *os.File) {
+		os.Stdout = orig
+	}(os.Stdout)
+	os.Stdout = w
+
+	// run command
+	go func() {
+		if err := run(context.Background(), args); err != nil {
+			t.Error(err)
+		}
+		w.Close()
+	}()
+
+	// compare generated file to reference output
+	r1 := bytes.NewBufferString(ref)
+	r2 := bufio.NewReader(r)
+	for n := 0; ; n++ {
+		b1, err1 := r1.ReadByte()
+		b2, err2 := r2.ReadByte()
+		if err1 != nil || err2 != nil {
+			if err1 != err2 {
+				t.Fatalf("mismatched errors: %q and %q\n", err1, err2)
+			}
+			if err1 != io.EOF {
+				t.Fatal(err1)
+			}
+			break
+		}
+		if b1 != b2 {
+			t.Fatalf("mismatched bytes: expected %#02x, got %#02x at position %d\n", b1, b2, n)
+		}
+	}
+	r.Close()
+}
+
+// TestEmpty compares the output produced when there are no files to convert
+// to a reference output.
+func TestEmpty(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as byte slices indexed by file paths.
+var bindata = map[string][]byte{}
+`
+	runTest(t, ref)
+}
+
+// TestFlags tests the -pkg and -map flags.
+func TestFlags(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package foo
+
+// MyData stores binary files as byte slices indexed by file paths.
+var MyData = map[string][]byte{}
+`
+	runTest(t, ref, "-p", "foo", "-m", "MyData")
+}
+
+// TestString tests the conversion to a map of strings.
+func TestString(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	"play/hello.go": "" +
+		"\x70\x61\x63\x6b\x61\x67\x65\x20\x6d\x61\x69\x6e\x0a\x0a\x69\x6d" +
+		"\x70\x6f\x72\x74\x20\x22\x66\x6d\x74\x22\x0a\x0a\x66\x75\x6e\x63" +
+		"\x20\x6d\x61\x69\x6e\x28\x29\x20\x7b\x0a\x09\x66\x6d\x74\x2e\x50" +
+		"\x72\x69\x6e\x74\x6c\x6e\x28\x22\x48\x65\x6c\x6c\x6f\x2c\x20\xe4" +
+		"\xb8\x96\xe7\x95\x8c\x22\x29\x0a\x7d\x0a",
+}
+`
+	runTest(t, ref, "-s", "-r", testdata, filepath.Join(testdata, "play", "hello.go"))
+}
+
+// TestLayout tests the -columns, -tab and -uppercase flags.
+func TestLayout(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as byte slices indexed by file paths.
+var bindata = map[string][]byte{
+	"play/hello.go": []byte{
+	    0x70, 0x61, 0x63, 0x6B, 0x61, 0x67, 0x65, 0x20, 0x6D, 0x61, 0x69, 0x6E, 0x0A, 0x0A, 0x69, 0x6D, 0x70, 0x6F, 0x72, 0x74,
+	    0x20, 0x22, 0x66, 0x6D, 0x74, 0x22, 0x0A, 0x0A, 0x66, 0x75, 0x6E, 0x63, 0x20, 0x6D, 0x61, 0x69, 0x6E, 0x28, 0x29, 0x20,
+	    0x7B, 0x0A, 0x09, 0x66, 0x6D, 0x74, 0x2E, 0x50, 0x72, 0x69, 0x6E, 0x74, 0x6C, 0x6E, 0x28, 0x22, 0x48, 0x65, 0x6C, 0x6C,
+	    0x6F, 0x2C, 0x20, 0xE4, 0xB8, 0x96, 0xE7, 0x95, 0x8C, 0x22, 0x29, 0x0A, 0x7D, 0x0A,
+	},
+}
+`
+	runTest(t, ref, "-columns", "20", "-tab", "    ", "-uppercase", "-r", testdata, filepath.Join(testdata, "play", "hello.go"))
+
+	for _, args := range [][]string{{"-columns", "-1"}, {"-tab", ""}, {"-tab", "\t-"}} {
+		if err := runGenerate(append(args, testdata)); err == nil || !strings.HasPrefix(err.Error(), "invalid "+args[0]) {
+			t.Errorf("%v: expected an invalid flag error, got %v", args, err)
+		}
+	}
+}
+
+// TestFiles tests the reference output when there is a hierarchy of files to convert.
+func TestFiles(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as byte slices indexed by file paths.
+var bindata = map[string][]byte{
+	"empty": []byte{},
+	"gopher.gif": []byte{
+		0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x10, 0x00, 0x10, 0x00, 0xf5, 0x27,
+		0x00, 0x17, 0x13, 0x11, 0x23, 0x20, 0x1f, 0x27, 0x2c, 0x2e, 0x25, 0x3a,
+		0x41, 0x27, 0x45, 0x4e, 0x31, 0x4e, 0x57, 0x38, 0x4e, 0x54, 0x2d, 0x57,
+		0x65, 0x37, 0x65, 0x75, 0x34, 0x68, 0x78, 0x4e, 0x73, 0x7b, 0xc3, 0x8c,
+		0x74, 0x48, 0x92, 0xaa, 0x54, 0x92, 0xa5, 0x4e, 0xa7, 0xc8, 0x7a, 0xb2,
+		0xc2, 0x72, 0xb3, 0xc8, 0x79, 0xbb, 0xcf, 0x74, 0xd5, 0xf3, 0x77, 0xdd,
+		0xff, 0x77, 0xdf, 0xff, 0x78, 0xdf, 0xff, 0x75, 0xe1, 0xff, 0x76, 0xe1,
+		0xfe, 0x79, 0xe0, 0xff, 0xa6, 0x99, 0x8e, 0x8c, 0x9c, 0xa0, 0x89, 0xa3,
+		0xa6, 0x8f, 0xbf, 0xca, 0x96, 0xc1, 0xcc, 0xdd, 0xd9, 0xd7, 0xe3, 0xdf,
+		0xdc, 0xd2, 0xe1, 0xe7, 0xe5, 0xe2, 0xe1, 0xf1, 0xeb, 0xea, 0xfb, 0xf6,
+		0xf5, 0xff, 0xfa, 0xf7, 0xff, 0xfc, 0xfb, 0xff, 0xfe, 0xfb, 0xff, 0xff,
+		0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x28, 0x00, 0x2c, 0x00, 0x00,
+		0x00, 0x00, 0x10, 0x00, 0x10, 0x00, 0x00, 0x06, 0x80, 0x40, 0x94, 0x70,
+		0x28, 0xfa, 0x78, 0x44, 0xc3, 0x24, 0x2a, 0xa4, 0x51, 0x28, 0x0a, 0x03,
+		0xc2, 0xc1, 0xa9, 0x09, 0x09, 0x3d, 0x06, 0x05, 0xc3, 0xe1, 0xb8, 0x78,
+		0x2f, 0x1d, 0x4e, 0x43, 0x61, 0xc0, 0x3a, 0x18, 0x90, 0x11, 0x09, 0xe2,
+		0x85, 0x9c, 0x4e, 0x90, 0xad, 0xc1, 0x90, 0x70, 0x80, 0x4e, 0x00, 0xc1,
+		0x85, 0x72, 0xcf, 0x3b, 0x10, 0x65, 0x06, 0x17, 0x7d, 0x7a, 0x83, 0x78,
+		0x7a, 0x65, 0x28, 0x82, 0x12, 0x6f, 0x10, 0x01, 0x01, 0x6e, 0x27, 0x12,
+		0x17, 0x06, 0x42, 0x82, 0x14, 0x5e, 0x1b, 0x0b, 0x0b, 0x19, 0x5e, 0x15,
+		0x94, 0x96, 0x5f, 0x15, 0x0b, 0x6f, 0x0b, 0x14, 0xa7, 0x14, 0x95, 0x8a,
+		0x5f, 0x5e, 0x11, 0x0f, 0xa8, 0xa7, 0x05, 0xa1, 0xac, 0xb4, 0x5e, 0xaa,
+		0x82, 0xb5, 0xac, 0x18, 0xb7, 0xb9, 0xb4, 0xbc, 0xbd, 0x5f, 0xbf, 0xc0,
+		0xa0, 0xab, 0xc3, 0xa0, 0x41, 0x00, 0x3b,
+	},
+	"play/bytes/11": []byte{
+		0x31, 0x30, 0x2b, 0x31, 0x20, 0x62, 0x79, 0x74, 0x65, 0x73, 0x21,
+	},
+	"play/bytes/12": []byte{
+		0x31, 0x32, 0x20, 0x62, 0x79, 0x74, 0x65, 0x73, 0x20, 0x6f, 0x6b, 0x3f,
+	},
+	"play/bytes/13": []byte{
+		0x6a, 0x75, 0x73, 0x74, 0x20, 0x31, 0x33, 0x20, 0x62, 0x79, 0x74, 0x65,
+		0x73,
+	},
+	"play/hello.go": []byte{
+		0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x20, 0x6d, 0x61, 0x69, 0x6e,
+		0x0a, 0x0a, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x20, 0x22, 0x66, 0x6d,
+		0x74, 0x22, 0x0a, 0x0a, 0x66, 0x75, 0x6e, 0x63, 0x20, 0x6d, 0x61, 0x69,
+		0x6e, 0x28, 0x29, 0x20, 0x7b, 0x0a, 0x09, 0x66, 0x6d, 0x74, 0x2e, 0x50,
+		0x72, 0x69, 0x6e, 0x74, 0x6c, 0x6e, 0x28, 0x22, 0x48, 0x65, 0x6c, 0x6c,
+		0x6f, 0x2c, 0x20, 0xe4, 0xb8, 0x96, 0xe7, 0x95, 0x8c, 0x22, 0x29, 0x0a,
+		0x7d, 0x0a,
+	},
+}
+`
+	runTest(t, ref, "-r", testdata, testdata)
+}
+
+// TestConsts tests the generation of constants for the file names.
+func TestConsts(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	"empty": "",
+	"play/bytes/11": "" +
+		"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21",
+}
+
+// Names of the files stored in bindata.
+const (
+	AssetEmpty       = "empty"
+	AssetPlayBytes11 = "play/bytes/11"
+)
+`
+	runTest(t, ref, "-s", "-const-prefix", "Asset", "-r", testdata,
+		filepath.Join(testdata, "empty"), filepath.Join(testdata, "play", "bytes", "11"))
+}
+
+// TestTinyGo tests the generation of a sorted slice for TinyGo.
+func TestTinyGo(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as strings sorted by file paths.
+// Use bindataLookup to find a file.
+var bindata = []struct {
+	Name string
+	Data string
+}{
+	{"empty", ""},
+	{"play/bytes/11", "" +
+		"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21"},
+}
+
+// bindataLookup returns the contents of the named file by binary search,
+// or false if there is no such file.
+func bindataLookup(name string) (string, bool) {
+	i, j := 0, len(bindata)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if bindata[h].Name < name {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	if i < len(bindata) && bindata[i].Name == name {
+		return bindata[i].Data, true
+	}
+	return "", false
+}
+`
+	runTest(t, ref, "-target", "tinygo", "-r", testdata,
+		filepath.Join(testdata, "empty"), filepath.Join(testdata, "play", "bytes", "11"))
+
+	if err := runGenerate([]string{"-target", "tinygo", "-readonly", testdata}); err == nil {
+		t.Error("expected error for -readonly")
+	}
+}
+
+// TestSliceLayout tests the generation of a sorted slice of byte slices.
+func TestSliceLayout(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as byte slices sorted by file paths.
+// Use bindataLookup to find a file.
+var bindata = []struct {
+	Name string
+	Data []byte
+}{
+	{"play/bytes/11", []byte{
+		0x31, 0x30, 0x2b, 0x31, 0x20, 0x62, 0x79, 0x74, 0x65, 0x73, 0x21,
+	}},
+}
+
+// bindataLookup returns the contents of the named file by binary search,
+// or false if there is no such file.
+func bindataLookup(name string) ([]byte, bool) {
+	i, j := 0, len(bindata)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if bindata[h].Name < name {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	if i < len(bindata) && bindata[i].Name == name {
+		return bindata[i].Data, true
+	}
+	return nil, false
+}
+`
+	runTest(t, ref, "-layout", "slice", "-r", testdata, filepath.Join(testdata, "play", "bytes", "11"))
+}
+
+// TestDoc tests the generation of the package documentation listing the files.
+func TestDoc(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+// Package main embeds the following files:
+//
+//	empty           0 bytes
+//	play/bytes/11  11 bytes
+package main
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	"empty": "",
+	"play/bytes/11": "" +
+		"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21",
+}
+`
+	runTest(t, ref, "-s", "-doc", "-r", testdata,
+		filepath.Join(testdata, "empty"), filepath.Join(testdata, "play", "bytes", "11"))
+}
+
+// runGenerated generates a file with the given arguments and runs it
+// along with a main file, returning the output of the program.
+func runGenerated(t *testing.T, main string, args ...string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds generated code")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate(append([]string{"-o", filepath.Join(dir, "bindata.go")}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(gobin, "run", "main.go", "bindata.go")
+	cmd.Dir = dir
+	b, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, b)
+	}
+	return string(b)
+}
+
+// TestFS tests the generated file system against testing/fstest.
+func TestFS(t *testing.T) {
+	const main = `package main
+
+import (
+	"fmt"
+	"testing/fstest"
+)
+
+func main() {
+	if err := fstest.TestFS(bindataFS{}, "empty", "play/hello.go", "play/bytes/11"); err != nil {
+		fmt.Println(err)
+	}
+}
+`
+	for _, flags := range [][]string{nil, {"-s"}, {"-layout", "slice"}, {"-layout", "blob"}, {"-compress", "gzip"}} {
+		args := append(flags, "-fs", "-r", testdata, filepath.Join(testdata, "play"), filepath.Join(testdata, "empty"))
+		if out := runGenerated(t, main, args...); out != "" {
+			t.Errorf("%v: %s", flags, out)
+		}
+	}
+}
+
+// TestWalk tests the generated walk of the files.
+func TestWalk(t *testing.T) {
+	const main = `package main
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+func main() {
+	bindataWalk(".", func(name string, d fs.DirEntry, err error) error {
+		fmt.Println(name, d.IsDir(), err)
+		if name == "play/bytes" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	fmt.Println(bindataWalk("missing", func(name string, d fs.DirEntry, err error) error { return err }))
+}
+`
+	const ref = `. true <nil>
+empty false <nil>
+play true <nil>
+play/bytes true <nil>
+play/hello.go false <nil>
+open missing: file does not exist
+`
+	for _, flags := range [][]string{nil, {"-layout", "slice"}} {
+		args := append(flags, "-walk", "-r", testdata, filepath.Join(testdata, "play"), filepath.Join(testdata, "empty"))
+		if out := runGenerated(t, main, args...); out != ref {
+			t.Errorf("%v: unexpected walk:\n%s", flags, out)
+		}
+	}
+	for _, flags := range [][]string{{"-layout", "vars"}, {"-obfuscate-keys", "salt"}} {
+		if err := runGenerate(append(flags, "-walk", testdata)); err == nil || !strings.Contains(err.Error(), "-walk") {
+			t.Errorf("%v: expected error naming -walk, got %v", flags, err)
+		}
+	}
+}
+
+// TestIter tests the iterator over the files.
+func TestIter(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	for name, data := range bindataAll() {
+		fmt.Printf("%s %q\n", name, data)
+		if name == "play/bytes/12" {
+			break
+		}
+	}
+}
+`
+	const want = "empty \"\"\nplay/bytes/11 \"10+1 bytes!\"\nplay/bytes/12 \"12 bytes ok?\"\n"
+	for _, flags := range [][]string{nil, {"-layout", "slice"}, {"-layout", "blob"}, {"-compress", "gzip"}, {"-readonly"}} {
+		args := append(flags, "-iter", "-r", testdata, filepath.Join(testdata, "play"), filepath.Join(testdata, "empty"))
+		if out := runGenerated(t, main, args...); out != want {
+			t.Errorf("%v: unexpected iteration:\n%s", flags, out)
+		}
+	}
+	if err := runGenerate([]string{"-iter", "-lang", "1.22", testdata}); err == nil {
+		t.Error("expected error with -lang 1.22")
+	}
+}
+
+// TestErrors tests the typed errors returned by the accessors.
+func TestErrors(t *testing.T) {
+	const main = `package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+func main() {
+	_, err := bindataRead("missing")
+	var nf bindataErrAssetNotFound
+	fmt.Println(err, errors.Is(err, os.ErrNotExist), errors.As(err, &nf) && nf.Name == "missing")
+	bindata["play/bytes/11"] = []byte("corrupted")
+	bindataCodecs["play/bytes/11"] = "gzip"
+	_, err = bindataRead("play/bytes/11")
+	var de bindataErrDecode
+	fmt.Println(errors.As(err, &de) && de.Name == "play/bytes/11", errors.Is(err, os.ErrNotExist))
+}
+`
+	const want = "bindata: file not found: missing true true\ntrue false\n"
+	if out := runGenerated(t, main, "-compress", "gzip", "-r", testdata, filepath.Join(testdata, "play", "bytes")); out != want {
+		t.Errorf("unexpected errors:\n%s", out)
+	}
+}
+
+// TestSpillOver tests the decompression of the large files to disk.
+func TestSpillOver(t *testing.T) {
+	src, spill := t.TempDir(), t.TempDir()
+	big := strings.Repeat("spilled ", 1000)
+	if err := os.WriteFile(filepath.Join(src, "big.txt"), []byte(big), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "small.txt"), []byte(strings.Repeat("kept ", 100)), 0666); err != nil {
+		t.Fatal(err)
+	}
+	main := fmt.Sprintf(`package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	bindataSpillDir = %q
+	for _, name := range []string{"big.txt", "small.txt"} {
+		data, _ := bindataRead(name)
+		r, err := bindataOpen(name)
+		if err != nil {
+			panic(err)
+		}
+		b, _ := io.ReadAll(r)
+		r.Close()
+		_, spilled := r.(*os.File)
+		fmt.Println(name, len(data), string(b) == string(data), spilled, err)
+	}
+}
+`, spill)
+	const want = "big.txt 8000 true true <nil>\nsmall.txt 500 true false <nil>\n"
+	for i := range 2 {
+		if out := runGenerated(t, main, "-compress", "gzip", "-spill-over", "1KB", "-r", src, src); out != want {
+			t.Errorf("run %d: unexpected output:\n%s", i, out)
+		}
+		// a corrupted file is decompressed again
+		files, _ := filepath.Glob(filepath.Join(spill, "*"))
+		if len(files) != 1 {
+			t.Fatalf("unexpected spilled files %v", files)
+		}
+		if err := os.WriteFile(files[0], []byte("corrupted"), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := runGenerate([]string{"-spill-over", "1KB", testdata}); err == nil {
+		t.Error("expected error without -compress")
+	}
+}
+
+// TestHook tests the hook called on each access.
+func TestHook(t *testing.T) {
+	tests := map[string][]string{
+		"bindataAsset(%q)":             {"-readonly"},
+		"bindataRead(%q)":              {"-compress", "gzip"},
+		"bindataLookup(%q)":            {"-layout", "slice"},
+		"bindataFS{}.ReadFile(%q)":     {"-fs"},
+		"bindataLocalized(%q, \"en\")": {"-localized", "en"},
+	}
+	for access, flags := range tests {
+		main := `package main
+
+import "fmt"
+
+func main() {
+	bindataOnAccess = func(name string) { fmt.Println(name) }
+	` + fmt.Sprintf(access, "11") + `
+	` + fmt.Sprintf(access, "missing") + `
+}
+`
+		args := append(flags, "-hook", "-r", filepath.Join(testdata, "play", "bytes"), filepath.Join(testdata, "play", "bytes"))
+		if out := runGenerated(t, main, args...); out != "11\n" {
+			t.Errorf("%v: unexpected accesses %q", flags, out)
+		}
+	}
+	for access, flags := range map[string][]string{"Assets.X11()": {"-tree", "Assets"}, "R{}.X11()": {"-receiver", "R"}} {
+		main := `package main
+
+import "fmt"
+
+func main() {
+	bindataOnAccess = func(name string) { fmt.Println(name) }
+	` + access + `
+}
+`
+		args := append(flags, "-hook", "-r", filepath.Join(testdata, "play", "bytes"), filepath.Join(testdata, "play", "bytes"))
+		if out := runGenerated(t, main, args...); out != "11\n" {
+			t.Errorf("%v: unexpected accesses %q", flags, out)
+		}
+	}
+	if err := runGenerate([]string{"-hook", testdata}); err == nil {
+		t.Error("expected error without accessor functions")
+	}
+}
+
+// TestLocalized tests the resolution of localized variants.
+func TestLocalized(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"m.json": "default", "m.en.json": "en", "m.fr.json": "fr", "m.fr-CA.json": "fr-CA", "x.de.txt": "de",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	for _, c := range [][2]string{{"m.json", "fr-CA"}, {"m.json", "fr_BE"}, {"m.json", "de"}, {"x.txt", "de-AT"}, {"x.txt", "it"}} {
+		data, ok := bindataLocalized(c[0], c[1])
+		fmt.Printf("%s %s: %s %v\n", c[0], c[1], data, ok)
+	}
+}
+`
+	const want = `m.json fr-CA: fr-CA true
+m.json fr_BE: fr true
+m.json de: en true
+x.txt de-AT: de true
+x.txt it:  false
+`
+	if out := runGenerated(t, main, "-localized", "en", "-r", dir, dir); out != want {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+// TestTyped tests the generic accessor decoding the files.
+func TestTyped(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"Name": "gopher", "Port": 8080}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	const main = `package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Config struct {
+	Name string
+	Port int
+}
+
+func main() {
+	calls := 0
+	decode := func(data []byte) (*Config, error) {
+		calls++
+		return bindataJSON[*Config](data)
+	}
+	c1, err := bindataAssetAs("config.json", decode)
+	fmt.Println(c1.Name, c1.Port, err)
+	c2, err := bindataAssetAs("config.json", decode)
+	fmt.Println(c1 == c2, calls, err)
+	m, err := bindataAssetAs("config.json", bindataJSON[map[string]any])
+	fmt.Println(m["Name"], err)
+	_, err = bindataAssetAs("missing.json", bindataJSON[Config])
+	fmt.Println(err)
+	_, err = bindataAssetAs("config.json", func([]byte) (int, error) { return 0, errors.New("bad") })
+	fmt.Println(err)
+}
+`
+	const want = `gopher 8080 <nil>
+true 1 <nil>
+gopher <nil>
+bindata: file not found: missing.json
+bindata: decode config.json: bad
+`
+	if out := runGenerated(t, main, "-typed", "json", "-r", dir, dir); out != want {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+// TestTemplates tests the function parsing the embedded templates.
+func TestTemplates(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"layout.tmpl":         `<p>{{template "pages/index.gotmpl" .}}</p>`,
+		"pages/index.gotmpl":  `{{upper .}}`,
+		"pages/ignored.html":  `{{`,
+		"partials/title.tmpl": `{{define "title"}}Title{{end}}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const main = `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	bindataTemplateFuncs = map[string]any{"upper": strings.ToUpper}
+	t, err := bindataTemplates()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(bindataTemplateFiles)
+	if err := t.ExecuteTemplate(os.Stdout, "layout.tmpl", "<b>"); err != nil {
+		panic(err)
+	}
+	fmt.Println()
+	t.ExecuteTemplate(os.Stdout, "title", nil)
+}
+`
+	tests := map[string]string{
+		"text": "[layout.tmpl pages/index.gotmpl partials/title.tmpl]\n<p><B></p>\nTitle",
+		"html": "[layout.tmpl pages/index.gotmpl partials/title.tmpl]\n<p>&lt;B&gt;</p>\nTitle",
+	}
+	for pkg, want := range tests {
+		if out := runGenerated(t, main, "-templates", pkg, "-r", dir, dir); out != want {
+			t.Errorf("%s: unexpected output:\n%s", pkg, out)
+		}
+	}
+}
+
+// TestOverride tests overriding the embedded files at run time.
+func TestOverride(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{"play/bytes/11": "patched", "play/new.txt": "new"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	for _, name := range []string{"play/bytes/11", "play/bytes/12", "play/new.txt"} {
+		data, ok := bindataData(name)
+		fmt.Printf("%s %v\n", data, ok)
+	}
+}
+`
+	tests := map[string]string{
+		"":  "10+1 bytes! true\n12 bytes ok? true\n false\n",
+		dir: "patched true\n12 bytes ok? true\n false\n",
+	}
+	for env, want := range tests {
+		t.Setenv("BINDATA_OVERRIDE_DIR", env)
+		if out := runGenerated(t, main, "-override", "BINDATA_OVERRIDE_DIR", "-r", testdata, filepath.Join(testdata, "play")); out != want {
+			t.Errorf("%q: unexpected output:\n%s", env, out)
+		}
+	}
+}
+
+// TestWriter tests streaming the files to a writer.
+func TestWriter(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Repeat("gopher\n", 1000)), 0666); err != nil {
+		t.Fatal(err)
+	}
+	const main = `package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+func main() {
+	h := sha256.New()
+	n, err := bindataWriteTo("big.txt", h)
+	fmt.Printf("%d %x %v\n", n, h.Sum(nil)[:4], err)
+	_, err = bindataWriteTo("missing.txt", h)
+	fmt.Println(err)
+}
+`
+	const want = "7000 4b0141f4 <nil>\nbindata: file not found: missing.txt\n"
+	for _, args := range [][]string{nil, {"-compress", "gzip"}, {"-compress", "flate"}, {"-compress", "auto", "-hook"}, {"-layout", "slice"}, {"-layout", "blob"}, {"-readonly", "-hook"}} {
+		args = append(args, "-writer", "-r", dir, dir)
+		if out := runGenerated(t, main, args...); out != want {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+}
+
+// TestSizes tests the functions returning the sizes of the files.
+func TestSizes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Repeat("gopher\n", 1000)), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "small.txt"), []byte("tiny"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println(bindataSize("big.txt"), bindataStoredSize("big.txt") < 7000, bindataStoredSize("small.txt"))
+	fmt.Println(bindataSize("missing.txt"), bindataStoredSize("missing.txt"), bindataTotalSize())
+}
+`
+	for _, args := range [][]string{{"-compress", "gzip"}, {"-compress", "auto", "-hook"}} {
+		args = append(args, "-sizes", "-r", dir, dir)
+		if out := runGenerated(t, main, args...); out != "7000 true 4\n-1 -1 7004\n" {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+	const plain = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println(bindataSize("big.txt") == bindataStoredSize("big.txt"), bindataTotalSize())
+}
+`
+	if out := runGenerated(t, plain, "-sizes", "-layout", "blob", "-r", dir, dir); out != "true 7004\n" {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+// TestStat tests the descriptions of the files.
+func TestStat(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	info, ok := bindataStat("play/bytes/11")
+	fmt.Printf("%v %s %d %v %v %x\n", ok, info.Name, info.Size, info.Mode, info.ModTime.IsZero(), info.Hash[:4])
+	_, ok = bindataStat("missing")
+	fmt.Println(ok)
+}
+`
+	for args, want := range map[string]string{
+		"-compress gzip": "true play/bytes/11 11 -rw-r--r-- true eab36655\nfalse\n",
+		"-meta time":     "true play/bytes/11 11 -rw-r--r-- false eab36655\nfalse\n",
+	} {
+		args := append(strings.Fields(args), "-stat", "-r", testdata, filepath.Join(testdata, "play", "bytes"))
+		if out := runGenerated(t, main, args...); out != want {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+}
+
+// TestGitMeta tests the descriptions of the files with their last git commits.
+func TestGitMeta(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	for _, name := range []string{"committed.txt", "new.txt"} {
+		info, _ := bindataStat(name)
+		fmt.Printf("%d %s %d|", len(info.Commit), info.Author, info.CommitTime.Unix())
+	}
+	fmt.Println()
+}
+`
+	git, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git command not found")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"committed.txt", "new.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "committed.txt"}, {"commit", "-q", "-m", "add"}} {
+		cmd := exec.Command(git, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Gopher", "GIT_AUTHOR_EMAIL=gopher@example.com",
+			"GIT_COMMITTER_NAME=Gopher", "GIT_COMMITTER_EMAIL=gopher@example.com", "GIT_COMMITTER_DATE=2020-01-02T03:04:05Z")
+		if b, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, b)
+		}
+	}
+	if out := runGenerated(t, main, "-stat", "-git-meta", "-r", dir, dir); out != "40 Gopher 1577934245|0  -62135596800|\n" {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if err := runGenerate([]string{"-git-meta", dir}); err == nil {
+		t.Error("expected error without -stat")
+	}
+}
+
+// TestPreload tests decompressing the files in advance.
+func TestPreload(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Repeat(name+"\n", 1000)), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const main = `package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func main() {
+	bindataPreload("a.txt", "missing.txt")
+	bindataPreloadConcurrency = 0
+	fmt.Println(bindataPreloadAll(context.Background()))
+	n := 0
+	bindataPreloaded.Range(func(key, value any) bool {
+		n++
+		return true
+	})
+	data, err := bindataRead("b.txt")
+	fmt.Println(n, len(data), err)
+	data[0] = 'x'
+	data, _ = bindataRead("b.txt")
+	fmt.Println(string(data[:5]))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fmt.Println(bindataPreloadAll(ctx))
+}
+`
+	for _, args := range [][]string{{"-compress", "gzip"}, {"-s", "-compress", "auto"}} {
+		args = append(args, "-preload", "-r", dir, dir)
+		if out := runGenerated(t, main, args...); out != "<nil>\n3 6000 <nil>\nb.txt\ncontext canceled\n" {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+	if err := runGenerate([]string{"-o", filepath.Join(dir, "out.go"), "-preload", dir}); err == nil {
+		t.Error("expected error without -compress")
+	}
+}
+
+// TestReadCopy tests that the files read are copies, even when stored raw.
+func TestReadCopy(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	data, _ := bindataRead("play/bytes/11")
+	data[0] = 'x'
+	data, _ = bindataRead("play/bytes/11")
+	fmt.Printf("%s %q\n", data, bindataCodecs["play/bytes/11"])
+}
+`
+	for _, codec := range []string{"gzip", "auto"} {
+		if out := runGenerated(t, main, "-compress", codec, "-r", testdata, filepath.Join(testdata, "play", "bytes")); out != "10+1 bytes! \"\"\n" {
+			t.Errorf("%s: unexpected output:\n%s", codec, out)
+		}
+	}
+}
+
+// TestServeHTTP tests serving the files with range requests.
+func TestServeHTTP(t *testing.T) {
+	const main = `package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+)
+
+func main() {
+	for _, name := range []string{"play/bytes/11", "play/bytes/14"} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Range", "bytes=3-6")
+		w := httptest.NewRecorder()
+		bindataServeHTTP(w, r, name)
+		fmt.Printf("%d %q %q\n", w.Code, w.Body.String(), w.Header().Get("Last-Modified"))
+	}
+}
+`
+	const want = "206 \"1 by\" \"\"\n404 \"404 page not found\\n\" \"\"\n"
+	for _, args := range [][]string{nil, {"-s", "-compress", "gzip"}, {"-layout", "slice"}, {"-readonly", "-hook"}} {
+		args = append(args, "-serve-http", "-r", testdata, filepath.Join(testdata, "play", "bytes"))
+		if out := runGenerated(t, main, args...); out != want {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+	t.Setenv("SOURCE_DATE_EPOCH", "86400")
+	out := runGenerated(t, main, "-serve-http", "-meta", "time", "-r", testdata, filepath.Join(testdata, "play", "bytes"))
+	if !strings.Contains(out, `"Fri, 02 Jan 1970 00:00:00 GMT"`) {
+		t.Errorf("missing modification time:\n%s", out)
+	}
+}
+
+// TestPrecompressed tests serving the gzip encodings of the files.
+func TestPrecompressed(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Repeat("gopher\n", 1000)), 0666); err != nil {
+		t.Fatal(err)
+	}
+	const main = `package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http/httptest"
+)
+
+func main() {
+	for _, accept := range []string{"", "deflate, gzip", "gzip;q=0"} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Encoding", accept)
+		w := httptest.NewRecorder()
+		bindataServeHTTP(w, r, "big.txt")
+		body := w.Body.Bytes()
+		if w.Header().Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(w.Body)
+			if err != nil {
+				panic(err)
+			}
+			body, _ = io.ReadAll(zr)
+		}
+		fmt.Printf("%q %q %q %d\n", w.Header().Get("Content-Encoding"), w.Header().Get("Content-Type"), w.Header().Get("Vary"), len(body))
+	}
+}
+`
+	const want = `"" "text/plain; charset=utf-8" "Accept-Encoding" 7000
+"gzip" "text/plain; charset=utf-8" "Accept-Encoding" 7000
+"" "text/plain; charset=utf-8" "Accept-Encoding" 7000
+`
+	for _, args := range [][]string{nil, {"-s"}} {
+		args = append(args, "-precompressed", "-r", dir, dir)
+		if out := runGenerated(t, main, args...); out != want {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+}
+
+// TestAccessors tests the accessors returning an error or panicking on missing files.
+func TestAccessors(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	data, err := bindataAsset(AssetPlayBytes11)
+	fmt.Printf("%s %v\n", data, err)
+	fmt.Printf("%s\n", bindataMustAsset(AssetPlayBytes12))
+	defer func() { fmt.Println(recover()) }()
+	bindataMustAsset("missing")
+}
+`
+	const want = "10+1 bytes! <nil>\n12 bytes ok?\nbindata: file not found: missing\n"
+	for _, args := range [][]string{{"error,panic"}, {"error,panic,const"}, {"error,panic", "-readonly"}} {
+		args = append([]string{"-accessors"}, append(args, "-const-prefix", "Asset", "-r", testdata, filepath.Join(testdata, "play", "bytes"))...)
+		if out := runGenerated(t, main, args...); out != want {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+	if err := runGenerate([]string{"-accessors", "const", "-const-prefix", "Asset", testdata}); err == nil {
+		t.Error("expected error for const accessors alone")
+	}
+}
+
+// TestEmptyFiles tests the accessors of empty files, and skipping them.
+func TestEmptyFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{".keep": "", "a.txt": "a"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	data, err := bindataAsset(".keep")
+	fmt.Println(data != nil, len(data), err)
+}
+`
+	for _, args := range [][]string{nil, {"-s"}, {"-compress", "gzip"}, {"-layout", "slice"}, {"-layout", "blob"}, {"-readonly"}, {"-bytes-via-string"}} {
+		args = append(args, "-accessors", "error", "-r", dir, dir)
+		if out := runGenerated(t, main, args...); out != "true 0 <nil>\n" {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+	if out := runGenerated(t, main, "-skip-empty", "-accessors", "error", "-r", dir, dir); out != "false 0 bindata: file not found: .keep\n" {
+		t.Errorf("-skip-empty: unexpected output:\n%s", out)
+	}
+}
+
+// TestRegister tests the registration of the files in the registry of the runtime package.
+func TestRegister(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds generated code")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	// a GOPATH providing the runtime package
+	gopath := t.TempDir()
+	pkg := filepath.Join(gopath, "src", filepath.FromSlash(runtimePkg))
+	if err := os.MkdirAll(filepath.Dir(pkg), 0777); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(wd, "..", "runtime"), pkg); err != nil {
+		t.Skip(err)
+	}
+
+	dir := t.TempDir()
+	const main = `package main
+
+import (
+	"fmt"
+
+	"github.com/simleb/bindata/runtime"
+)
+
+func main() {
+	for _, b := range runtime.Bundles() {
+		data, ok := b.Read(b.Names[0])
+		fmt.Printf("%s %v %q %v\n", b.Name, b.Names, data, ok)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate([]string{"-o", filepath.Join(dir, "bindata.go"), "-register", "bytes", "-compress", "gzip", "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(gobin, "run", "main.go", "bindata.go")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOPATH="+gopath, "GO111MODULE=off")
+	b, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, b)
+	}
+	const want = "bytes [play/bytes/11 play/bytes/12 play/bytes/13] \"10+1 bytes!\" true\n"
+	if string(b) != want {
+		t.Errorf("unexpected output:\n%s", b)
+	}
+	if err := runGenerate([]string{"-register", "bytes", "-obfuscate-keys", "salt", testdata}); err == nil {
+		t.Error("expected error for -register with -obfuscate-keys")
+	}
+}
+
+// TestHeaderFooter tests the insertion of a header, a footer and a custom generated comment.
+func TestHeaderFooter(t *testing.T) {
+	dir := t.TempDir()
+	header, footer := filepath.Join(dir, "header.txt"), filepath.Join(dir, "footer.txt")
+	if err := os.WriteFile(header, []byte("// Copyright 2026 The Authors.\n\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(footer, []byte("// End of file."), 0666); err != nil {
+		t.Fatal(err)
+	}
+	const ref = `// Copyright 2026 The Authors.
+
+// Code generated by make assets. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	"empty": "",
+}
+
+// End of file.
+`
+	runTest(t, ref, "-s", "-header", header, "-footer", footer, "-generated", "Code generated by make assets. DO NOT EDIT.",
+		"-r", testdata, filepath.Join(testdata, "empty"))
+}
+
+// TestBlobLayout tests the generation of a blob holding all the files.
+func TestBlobLayout(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindataBlob holds the contents of all the files, concatenated.
+const bindataBlob = "" +
+	"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21\x31\x32\x20\x62\x79" +
+	"\x74\x65\x73\x20\x6f\x6b\x3f"
+
+// bindata stores the offsets and sizes of the files in bindataBlob sorted by file paths.
+// Use bindataLookup to find a file.
+var bindata = []struct {
+	Name         string
+	Offset, Size int
+}{
+	{"a/11", 0, 11},
+	{"b/11", 0, 11},
+	{"b/12", 11, 12},
+}
+
+// bindataLookup returns the contents of the named file by binary search,
+// or false if there is no such file.
+func bindataLookup(name string) (string, bool) {
+	i, j := 0, len(bindata)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if bindata[h].Name < name {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	if i < len(bindata) && bindata[i].Name == name {
+		return bindataBlob[bindata[i].Offset : bindata[i].Offset+bindata[i].Size], true
+	}
+	return "", false
+}
+`
+	dir := filepath.Join(testdata, "play", "bytes")
+	runTest(t, ref, "-layout", "blob", filepath.Join(dir, "11")+":a", filepath.Join(dir, "11")+":b",
+		filepath.Join(dir, "12")+":b")
+}
+
+// TestVarsLayout tests that the linker drops the files whose accessors are
+// not used with -layout vars.
+func TestVarsLayout(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Printf("%s|%s|%s\n", bindataUsedTxt(), Assets.UsedTxt(), Files{}.UsedTxt())
+}
+`
+	if testing.Short() {
+		t.Skip("builds generated code")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	in, dir := t.TempDir(), t.TempDir()
+	for name, data := range map[string]string{"used.txt": "used file", "unused.txt": "unused file bindata-dropped-by-linker"} {
+		if err := os.WriteFile(filepath.Join(in, name), []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate([]string{"-layout", "vars", "-s", "-tree", "Assets", "-receiver", "Files", "-o", filepath.Join(dir, "bindata.go"), "-r", in, in}); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(gobin, "build", "-o", "main", "main.go", "bindata.go")
+	cmd.Dir = dir
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, b)
+	}
+	bin, err := os.ReadFile(filepath.Join(dir, "main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(bin, []byte("used file")) || bytes.Contains(bin, []byte("bindata-dropped-by-linker")) {
+		t.Error("expected only the file used in the binary")
+	}
+	out, err := exec.Command(filepath.Join(dir, "main")).Output()
+	if err != nil || string(out) != "used file|used file|used file\n" {
+		t.Errorf("unexpected output %q (%v)", out, err)
+	}
+
+	for _, flag := range []string{"-fs", "-readonly", "-compress=gzip"} {
+		if err := runGenerate([]string{"-layout", "vars", flag, testdata}); err == nil {
+			t.Errorf("expected an error with %s", flag)
+		}
+	}
+}
+
+// TestVersion tests the generation of the fingerprint of the files.
+func TestVersion(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as byte slices indexed by file paths.
+var bindata = map[string][]byte{
+	"empty": []byte{},
+}
+
+// bindataVersion is a fingerprint of the names and contents of the files stored in bindata.
+const bindataVersion = "93366a140c4f9a6f"
+`
+	runTest(t, ref, "-bundle-version", "-r", testdata, filepath.Join(testdata, "empty"))
+}
+
+// TestPerDirOutput tests the generation of one file per input directory.
+func TestPerDirOutput(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"web/web.go":    "package web\n",
+		"data/hi.txt":   "hi",
+		"data/sub/a.go": "package ignored\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	web, data := filepath.Join(dir, "web"), filepath.Join(dir, "data")
+	for i := 0; i < 2; i++ { // the second run must not embed the first output
+		runTest(t, "", "-s", "-per-dir-output", "-o", "gen.go", web, data)
+	}
+
+	refs := map[string]string{
+		web: `// Code generated by bindata. DO NOT EDIT.
+
+package web
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	"web.go": "" +
+		"\x70\x61\x63\x6b\x61\x67\x65\x20\x77\x65\x62\x0a",
+}
+`,
+		data: `// Code generated by bindata. DO NOT EDIT.
+
+package data
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	"hi.txt": "" +
+		"\x68\x69",
+	"sub/a.go": "" +
+		"\x70\x61\x63\x6b\x61\x67\x65\x20\x69\x67\x6e\x6f\x72\x65\x64\x0a",
+}
+`,
+	}
+	for d, ref := range refs {
+		b, err := os.ReadFile(filepath.Join(d, "gen.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != ref {
+			t.Errorf("%s: unexpected output:\n%s", d, b)
+		}
+	}
+}
+
+// TestHashNames tests the storage of files under content-addressed keys.
+func TestHashNames(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+import (
+	"strings"
+)
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	"play/bytes/11.eab36655": "" +
+		"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21",
+}
+
+// bindataHashed maps the file names to their content-addressed keys in bindata.
+var bindataHashed = map[string]string{
+	"play/bytes/11": "play/bytes/11.eab36655",
+}
+
+// bindataRewrite replaces the file names referenced in s (e.g. in HTML or CSS)
+// with their content-addressed keys in bindata.
+func bindataRewrite(s string) string {
+	return bindataRewriter.Replace(s)
+}
+
+// bindataRewriter replaces the file names with their content-addressed keys, longest names first.
+var bindataRewriter = strings.NewReplacer(
+	"play/bytes/11", "play/bytes/11.eab36655",
+)
+`
+	runTest(t, ref, "-s", "-hash-names", "-r", testdata, filepath.Join(testdata, "play", "bytes", "11"))
+}
+
+// TestReadOnly tests the generation of read-only accessors.
+func TestReadOnly(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+import (
+	"unsafe"
+)
+
+// dataFiles stores binary files as strings indexed by file paths.
+// Use DataAsset or DataAssetUnsafe to access the data.
+var dataFiles = map[string]string{
+	"play/bytes/11": "" +
+		"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21",
+}
+
+// DataAsset returns a copy of the contents of the named file,
+// or false if there is no such file.
+func DataAsset(name string) ([]byte, bool) {
+	s, ok := dataFiles[name]
+	if !ok {
+		return nil, false
+	}
+	return []byte(s), true
+}
+
+// DataAssetUnsafe returns the contents of the named file without copying them,
+// or false if there is no such file. The returned slice must not be modified.
+func DataAssetUnsafe(name string) ([]byte, bool) {
+	s, ok := dataFiles[name]
+	if !ok {
+		return nil, false
+	}
+	if len(s) == 0 {
+		return []byte{}, true // unsafe.StringData is unspecified for empty strings
+	}
+	return unsafe.Slice(unsafe.StringData(s), len(s)), true
+}
+`
+	runTest(t, ref, "-readonly", "-m", "Data", "-r", testdata, filepath.Join(testdata, "play", "bytes", "11"))
+}
+
+// TestSPA tests the generation of an HTTP handler with a fallback file.
+func TestSPA(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	"11": "" +
+		"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21",
+}
+
+// bindataHandler returns an HTTP handler serving the files of bindata,
+// falling back to "11" for unknown paths (client-side routing).
+func bindataHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		data, ok := bindata[name]
+		if !ok {
+			name = "11"
+			data = bindata[name]
+		}
+		http.ServeContent(w, r, name, time.Time{}, strings.NewReader(data))
+	})
+}
+`
+	dir := filepath.Join(testdata, "play", "bytes")
+	runTest(t, ref, "-s", "-spa", "11", "-r", dir, filepath.Join(dir, "11"))
+}
+
+// TestBytesViaString tests the access to data saved as strings as byte slices.
+func TestBytesViaString(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindataFiles stores binary files as strings indexed by file paths.
+// Use bindataBytes to access the data as byte slices.
+var bindataFiles = map[string]string{
+	"play/bytes/11": "" +
+		"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21",
+}
+
+// bindataBytes returns the contents of the named file converted to a byte slice,
+// or nil if there is no such file.
+func bindataBytes(name string) []byte {
+	s, ok := bindataFiles[name]
+	if !ok {
+		return nil
+	}
+	return []byte(s)
+}
+`
+	runTest(t, ref, "-bytes-via-string", "-r", testdata, filepath.Join(testdata, "play", "bytes", "11"))
+}
+
+// TestComments tests the comments giving the origin of the files.
+func TestComments(t *testing.T) {
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	// testdata/empty: 0 bytes, sha256 e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+	"empty": "",
+	// testdata/play/bytes/11: 11 bytes, sha256 eab36655b67aa49cfd1e046fa37128203ca04f78e25fff38b2bc4b5b51ab702a
+	"play/bytes/11": "" +
+		"\x31\x30\x2b\x31\x20\x62\x79\x74\x65\x73\x21",
+}
+`
+	runTest(t, ref, "-s", "-comments", "-r", "testdata",
+		filepath.Join("testdata", "empty"), filepath.Join("testdata", "play", "bytes", "11"))
+}
+
+// TestWeirdNames tests file names with quotes, newlines and bytes which are
+// not valid UTF-8, in the keys and in the comments.
+func TestWeirdNames(t *testing.T) {
+	const main = `package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+func main() {
+	for _, name := range slices.Sorted(maps.Keys(bindata)) {
+		fmt.Printf("%q %s\n", name, bindata[name])
+	}
+}
+`
+	dir := t.TempDir()
+	names := []string{"weird\"name.js", "new\nline.txt", "bad\xff.txt", strings.Repeat("long/", 40) + "name.txt"}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name[:3]), 0666); err != nil {
+			t.Skipf("file system does not support %q: %v", name, err)
+		}
+	}
+	want := `"bad\xff.txt" bad
+` + fmt.Sprintf("%q lon\n", strings.Repeat("long/", 40)+"name.txt") + `"new\nline.txt" new
+"weird\"name.js" wei
+`
+	for _, args := range [][]string{{"-s", "-comments"}, {"-doc"}, {"-doc", "-comments"}} {
+		if out := runGenerated(t, main, append(args, "-r", dir, dir)...); out != want {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+
+	// JSON cannot represent names which are not valid UTF-8
+	if err := runGenerate([]string{"-emit", "json", "-o", filepath.Join(dir, "bundle.json"), "-r", dir, dir}); err == nil || !strings.Contains(err.Error(), "invalid UTF-8") {
+		t.Errorf("expected invalid UTF-8 error, got %v", err)
+	}
+}
+
+// TestGofmt tests that the generated code is formatted as by gofmt, whatever
+// the layout and the options.
+func TestGofmt(t *testing.T) {
+	// empty files printed on single lines, with keys long enough to break
+	// the alignment of the maps
+	dir := t.TempDir()
+	for _, name := range []string{"a", "exec.sh", "z/long/enough/to/break/the/alignment/of/the/maps.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inputs := [][]string{
+		{"-r", testdata, testdata},
+		{"-r", testdata, filepath.Join(testdata, "play", "bytes")},
+		{"-r", testdata, filepath.Join(testdata, "empty")},
+		{"-r", dir, dir},
+	}
+	layouts := [][]string{nil, {"-layout", "slice"}, {"-layout", "blob"}, {"-layout", "vars"}, {"-target", "tinygo"}, {"-s"}}
+	options := [][]string{
+		nil, {"-sizes"}, {"-stat"}, {"-hash-names"}, {"-embed-over", "5", "-comments"}, {"-compress", "gzip"}, {"-compress", "flate"},
+		{"-precompressed"}, {"-meta", "mode,time"}, {"-meta", "exec"}, {"-migrations"}, {"-fs"}, {"-walk"}, {"-readonly"},
+		{"-bytes-via-string"}, {"-comments"}, {"-doc"}, {"-tree", "Assets"}, {"-receiver", "R"},
+		{"-accessors", "error,panic,const", "-const-prefix", "File"}, {"-typed", "json"}, {"-templates", "text"},
+		{"-override", "X"}, {"-register", "b"}, {"-writer"}, {"-hook", "-fs"}, {"-iter"}, {"-localized", "en"},
+		{"-serve-http"}, {"-spa", "empty"}, {"-obfuscate-keys", "salt"}, {"-patches"}, {"-bundle-version"},
+		{"-compress", "gzip", "-spill-over", "5"}, {"-compress", "gzip", "-preload"}, {"-max-mem", "1"},
+		{"-columns", "5", "-uppercase"}, {"-max-mem", "1", "-columns", "1"},
+	}
+	for _, in := range inputs {
+		for _, layout := range layouts {
+			for _, opts := range options {
+				out := filepath.Join(t.TempDir(), "out.go")
+				args := append(append(append([]string{"-o", out}, layout...), opts...), in...)
+				if err := runGenerate(args); err != nil {
+					continue // options which cannot be combined
+				}
+				b, err := os.ReadFile(out)
+				if err != nil {
+					t.Fatal(err)
+				}
+				formatted, err := format.Source(b)
+				if err != nil {
+					t.Errorf("%v: %v", args, err)
+				} else if !bytes.Equal(formatted, b) {
+					t.Errorf("%v: not formatted as by gofmt:\n%s", args, firstDiff(b, formatted))
+				}
+			}
+		}
+	}
+}
+
+// firstDiff returns the first line differing between got and want.
+func firstDiff(got, want []byte) string {
+	g, w := strings.Split(string(got), "\n"), strings.Split(string(want), "\n")
+	for i := range g {
+		if i >= len(w) || g[i] != w[i] {
+			return fmt.Sprintf("line %d: %q, expected %q", i+1, g[i], w[min(i, len(w)-1)])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Size is a number of bytes that can be written with a unit, e.g. "10MB".
+type Size int64
+
+// units are the multipliers of the size units, longest suffixes first.
+var units = []struct {
+	suffix string
+	n      int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9},
+	{"K", 1e3}, {"M", 1e6}, {"G", 1e9},
+	{"B", 1},
+}
+
+// ParseSize parses a size such as "512", "10MB" or "1.5GiB".
+func ParseSize(s string) (Size, error) {
+	num, mult := strings.TrimSpace(s), int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(strings.ToUpper(num), strings.ToUpper(u.suffix)) {
+			num, mult = strings.TrimSpace(num[:len(num)-len(u.suffix)]), u.n
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return Size(f * float64(mult)), nil
+}
+
+// String formats the size with a decimal unit.
+func (s Size) String() string {
+	switch {
+	case s >= 1e9:
+		return strconv.FormatFloat(float64(s)/1e9, 'f', -1, 64) + "GB"
+	case s >= 1e6:
+		return strconv.FormatFloat(float64(s)/1e6, 'f', -1, 64) + "MB"
+	case s >= 1e3:
+		return strconv.FormatFloat(float64(s)/1e3, 'f', -1, 64) + "KB"
+	}
+	return strconv.FormatInt(int64(s), 10) + "B"
+}
+
+// Set parses the size from a command line flag.
+func (s *Size) Set(value string) error {
+	size, err := ParseSize(value)
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+// UnmarshalJSON parses the size from a JSON string or number.
+func (s *Size) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		var n int64
+		if err := json.Unmarshal(b, &n); err != nil {
+			return fmt.Errorf("invalid size %s", b)
+		}
+		*s = Size(n)
+		return nil
+	}
+	return s.Set(str)
+}
+
+// offenders is the number of files listed when a budget is exceeded.
+const offenders = 5
+
+// CheckBudgets checks that the total size of the assets is within budget,
+// and that the size of the assets in each directory is within its own budget.
+// A zero budget means no limit. All exceeded budgets are reported together,
+// along with the largest files responsible.
+func CheckBudgets(assets map[string]*Asset, budget Size, budgets map[string]Size) error {
+	var msgs []string
+	check := func(scope string, limit Size, match func(key string) bool) {
+		if limit == 0 {
+			return
+		}
+		var total Size
+		var files []*Asset
+		for key, a := range assets {
+			if match(filepath.ToSlash(key)) {
+				total += Size(a.Len())
+				files = append(files, a)
+			}
+		}
+		if total <= limit {
+			return
+		}
+		sort.Slice(files, func(i, j int) bool {
+			if files[i].Len() != files[j].Len() {
+				return files[i].Len() > files[j].Len()
+			}
+			return files[i].Name < files[j].Name
+		})
+		if len(files) > offenders {
+			files = files[:offenders]
+		}
+		msg := fmt.Sprintf("%s: %v exceeds budget of %v; largest files:", scope, total, limit)
+		for _, a := range files {
+			msg += fmt.Sprintf("\n\t%s\t%v", a.Name, Size(a.Len()))
+		}
+		msgs = append(msgs, msg)
+	}
+
+	check("total", budget, func(string) bool { return true })
+	dirs := make([]string, 0, len(budgets))
+	for dir := range budgets {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		clean := path.Clean(filepath.ToSlash(dir))
+		check(dir, budgets[dir], func(key string) bool {
+			return clean == "." || strings.HasPrefix(key, clean+"/")
+		})
+	}
+
+	if msgs != nil {
+		return fmt.Errorf("budget exceeded:\n%s", strings.Join(msgs, "\n"))
+	}
+	return nil
+}
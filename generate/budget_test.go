@@ -0,0 +1,63 @@
+package generate
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseSize tests the parsing of sizes with units.
+func TestParseSize(t *testing.T) {
+	tests := map[string]Size{
+		"512":    512,
+		"10MB":   10e6,
+		"1.5kib": 1536,
+		"2 G":    2e9,
+		"7B":     7,
+	}
+	for in, out := range tests {
+		got, err := ParseSize(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != out {
+			t.Errorf("%s: expected %d, got %d", in, out, got)
+		}
+	}
+	for _, in := range []string{"", "MB", "-1KB", "ten"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
+
+// TestCheckBudgets tests the enforcement of total and per-directory budgets.
+func TestCheckBudgets(t *testing.T) {
+	assets := map[string]*Asset{
+		"a/x":   {Name: "a/x", Data: make([]byte, 60)},
+		"a/y":   {Name: "a/y", Data: make([]byte, 30)},
+		"ab/z":  {Name: "ab/z", Data: make([]byte, 50)},
+		"other": {Name: "other", Data: make([]byte, 10)},
+	}
+	if err := CheckBudgets(assets, 150, map[string]Size{"a": 90, "ab/": 50}); err != nil {
+		t.Fatal(err)
+	}
+	err := CheckBudgets(assets, 100, map[string]Size{"a": 80, "ab": 100})
+	if err == nil {
+		t.Fatal("expected budget error")
+	}
+	const ref = `budget exceeded:
+total: 150B exceeds budget of 100B; largest files:
+	a/x	60B
+	ab/z	50B
+	a/y	30B
+	other	10B
+a: 90B exceeds budget of 80B; largest files:
+	a/x	60B
+	a/y	30B`
+	if msg := err.Error(); msg != ref {
+		t.Errorf("unexpected report:\n%s", msg)
+	}
+	if strings.Contains(err.Error(), "ab:") {
+		t.Error("directory within budget reported")
+	}
+}
@@ -0,0 +1,192 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A setting is an option of a generation named after the flag setting it,
+// once resolved: several flags may resolve to the same template variables.
+type setting struct {
+	Flag string
+	Set  bool
+}
+
+// anySet reports whether one of settings is set.
+func anySet(settings []setting) bool {
+	for _, s := range settings {
+		if s.Set {
+			return true
+		}
+	}
+	return false
+}
+
+// A requirement is an option set only along with another.
+type requirement struct {
+	setting
+	Needs string // what the option requires
+	Met   bool
+}
+
+// A conflict forbids settings along with an option.
+type conflict struct {
+	Set    bool      // the option is set
+	Format string    // error formatted with the flag of the forbidden setting
+	With   []setting // settings forbidden along with the option
+}
+
+// options are the settings of a generation outside of its template
+// variables, once resolved from the command line.
+type options struct {
+	out    string // output file, empty for stdout
+	stdout bool   // -o -
+	inputs bool   // paths given on the command line or by the profile
+
+	mirror, compress, layout, target, emit, emitFlag string
+	spa, tree, accessors, constPrefix, overrideKey   string
+	lockFile, reportFile, provenanceFile             string
+	jsonManifest, tsManifest                         string
+
+	hashNames, perDir, comments, version, doc, updateLock bool
+	layers, goPkgs, copies, aliases, groups               bool
+
+	embedOver, spillOver Size
+}
+
+// check returns an error if options of the generation cannot be combined.
+// The options are checked after their resolution into gen.vars, in a fixed
+// order, so that a command line always reports the same error.
+func (gen *generator) check(o *options) error {
+	v := &gen.vars
+	// layout names the flag choosing a layout other than the map, if any
+	layout := setting{"-layout " + o.layout, v.Slice || v.PerFile}
+	if o.layout == "map" && o.target != "" {
+		layout.Flag = "-target " + o.target
+	}
+	var (
+		readOnly       = setting{"-readonly", v.ReadOnly}
+		bytesViaString = setting{"-bytes-via-string", v.BytesViaString}
+		compress       = setting{"-compress", o.compress != ""}
+		precompressed  = setting{"-precompressed", v.Precompressed}
+		hashNames      = setting{"-hash-names", o.hashNames}
+		salt           = setting{"-obfuscate-keys", v.Salt != ""}
+		fs             = setting{"-fs", v.FS && !v.Migrate && !v.Walk}
+		walk           = setting{"-walk", v.Walk}
+		migrations     = setting{"-migrations", v.Migrate}
+		iter           = setting{"-iter", v.Iter}
+		meta           = setting{"-meta", v.Meta}
+		localized      = setting{"-localized", v.Localized != ""}
+		typed          = setting{"-typed", v.Typed}
+		templates      = setting{"-templates", v.Templates != ""}
+		override       = setting{"-override", v.Override != ""}
+		accessors      = setting{"-accessors", o.accessors != ""}
+		writer         = setting{"-writer", v.Writer}
+		register       = setting{"-register", v.Register != ""}
+		sizes          = setting{"-sizes", v.Sizes}
+		stat           = setting{"-stat", v.Stat}
+		serveHTTP      = setting{"-serve-http", v.ServeHTTP && !v.Precompressed}
+		spa            = setting{"-spa", o.spa != ""}
+		tree           = setting{"-tree", o.tree != ""}
+		receiver       = setting{"-receiver", v.Receiver != ""}
+		hook           = setting{"-hook", v.Hook}
+		only           = setting{"-only", len(gen.only) > 0}
+		routes         = setting{"-o-for", len(gen.routes) > 0}
+		split          = o.groups || routes.Set // files written to separate files
+		perDir         = setting{"-per-dir-output", o.perDir}
+		emitter        = setting{o.emitFlag, o.emitFlag != ""}
+	)
+	// functions generated to access the files, which call the hook
+	accessorFuncs := []setting{
+		readOnly, bytesViaString, compress, layout, salt, fs, walk, migrations, iter, localized,
+		typed, templates, override, accessors, writer, register, serveHTTP, precompressed, spa, tree, receiver,
+	}
+
+	for _, r := range []requirement{
+		{setting{"-preload", v.Preload}, "-compress", compress.Set},
+		{setting{"-spill-over", o.spillOver > 0}, "-compress", compress.Set},
+		{setting{"-update-lock", o.updateLock}, "-lock", o.lockFile != ""},
+		{setting{"-embed-over", o.embedOver > 0}, "an output file (-o)", o.out != ""},
+		{only, "an output file (-o)", o.out != ""},
+		{hook, "generated accessor functions (e.g. -readonly, -compress, -layout slice or -fs)", anySet(accessorFuncs)},
+		{setting{"-accessors const", v.Names}, "-const-prefix and error or panic accessors", o.constPrefix != "" && (v.AssetError || v.AssetPanic)},
+		{setting{"-git-meta", v.GitMeta}, "-stat", v.Stat},
+		{setting{"-override-key", o.overrideKey != ""}, "-override", v.Override != ""},
+	} {
+		if r.Set && !r.Met {
+			return fmt.Errorf("%s requires %s", r.Flag, r.Needs)
+		}
+	}
+
+	for _, c := range []conflict{
+		{o.stdout, "-o - cannot be combined with %s", []setting{perDir}},
+		{o.layers, "-layer cannot be combined with %s", []setting{perDir}},
+		{o.goPkgs, "-go-pkg cannot be combined with %s", []setting{perDir}},
+		{o.mirror != "", "-mirror takes the inputs from " + strings.ReplaceAll(o.mirror, "%", "%%") + ": it cannot be combined with %s", []setting{
+			{"inputs", o.inputs}, {"-go-pkg", o.goPkgs}, {"-layer", o.layers}, perDir, only,
+		}},
+		{perDir.Set, "%s cannot be combined with -per-dir-output", []setting{
+			{"sources", len(gen.sources) > 0}, routes, {"-report", o.reportFile != ""}, {"-provenance", o.provenanceFile != ""}, {"-lock", o.lockFile != ""},
+			{"-o-copy", o.copies}, {"-json-manifest", o.jsonManifest != ""}, {"-ts-manifest", o.tsManifest != ""},
+		}},
+		{readOnly.Set, "%s cannot be combined with -readonly", []setting{compress}},
+		{precompressed.Set, "%s cannot be combined with -precompressed", []setting{compress}},
+		{o.updateLock, "%s cannot be combined with -update-lock", []setting{only}},
+		{only.Set, "%s cannot be combined with -only", []setting{hashNames, routes, meta}},
+		{templates.Set, "%s cannot be combined with -templates", []setting{hashNames}},
+		{tree.Set, "%s cannot be combined with -tree", []setting{hashNames, compress}},
+		{receiver.Set, "%s cannot be combined with -receiver", []setting{hashNames, compress}},
+		{v.Patch, "%s cannot be combined with -patches", []setting{hashNames, salt, {"-layout vars", v.PerFile}}},
+		{v.Slice, "%s is not supported by the slice layout", []setting{readOnly, bytesViaString, compress, spa, routes}},
+		{o.emitFlag == "-t", "%s cannot be combined with -t", []setting{{"-emit", o.emit != "go"}}},
+		{emitter.Set, "%s cannot be combined with " + o.emitFlag, []setting{compress, hashNames, salt, only, perDir}},
+		// options needing the contents of all the files in memory
+		{gen.maxMem > 0, "%s cannot be combined with -max-mem", []setting{
+			compress, precompressed, hashNames, {"-layout blob", o.layout == "blob"}, {"-layout vars", v.PerFile},
+			{"-comments", o.comments}, {"-bundle-version", o.version}, {"-lock", o.lockFile != ""}, stat, emitter,
+		}},
+		// the files embedded with go:embed are added to the map on initialization
+		{o.embedOver > 0, "%s cannot be combined with -embed-over", []setting{
+			layout, {"-max-mem", gen.maxMem > 0}, {"-o-copy", o.copies}, emitter,
+		}},
+		{salt.Set, "%s cannot be combined with -obfuscate-keys", []setting{
+			readOnly, bytesViaString, compress, fs, walk, iter, meta, localized, typed, templates, override, accessors,
+			writer, register, sizes, stat, serveHTTP, precompressed, spa, tree, receiver, {"-const-prefix", o.constPrefix != ""},
+			hashNames, {"-doc", o.doc}, {"-comments", o.comments}, only, routes,
+		}},
+		// options generating code looking up the files by name
+		{v.PerFile, "%s is not supported by the vars layout", []setting{
+			readOnly, bytesViaString, compress, precompressed, fs, walk, meta, localized, typed, templates, override, accessors,
+			writer, register, sizes, stat, serveHTTP, spa, hook, migrations, iter, hashNames, salt, routes,
+		}},
+		// the aliases are added to the map on initialization, under the keys of the files
+		{o.aliases, "aliases cannot be combined with %s", []setting{layout, hashNames, salt, emitter}},
+		{split, "groups and -o-for require %s", []setting{{"an output file (-o)", o.out == ""}}},
+		{split, "groups and -o-for cannot be combined with %s", []setting{
+			{"-o-copy", o.copies}, hashNames, salt, register, sizes, stat, emitter, {"-max-mem", gen.maxMem > 0},
+		}},
+		{o.groups, "groups are not supported by the %s", []setting{{"slice layout", v.Slice}, {"vars layout", v.PerFile}}},
+		{o.groups, "%s cannot be combined with groups", []setting{only}},
+	} {
+		if !c.Set {
+			continue
+		}
+		for _, s := range c.With {
+			if s.Set {
+				return fmt.Errorf(c.Format, s.Flag)
+			}
+		}
+	}
+
+	return checkLang(v.Go, []langFeature{
+		{"-fs", v.FS, 16, "io/fs"},
+		{"-embed-over", o.embedOver > 0, 16, "go:embed"},
+		{"-spill-over", o.spillOver > 0, 16, "os.CreateTemp"},
+		{"-override", v.Override != "", 16, "io/fs"},
+		{"-typed", v.Typed, 18, "generics"},
+		{"-meta", v.Meta, 20, "filepath.IsLocal"},
+		{"-stat", v.Stat, 16, "io/fs"},
+		{"-iter", v.Iter, 23, "iter.Seq2"},
+		{"-readonly", v.ReadOnly, 20, "unsafe.StringData"},
+	})
+}
@@ -0,0 +1,55 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCheck tests the errors reported for options which cannot be combined.
+func TestCheck(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "bindata.json")
+	if err := os.WriteFile(config, []byte(`{"groups": {"gif": ["*.gif"]}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-layout", "vars", "-stat", "-sizes", "-writer"}, "-writer is not supported by the vars layout"},
+		{[]string{"-obfuscate-keys", "s", "-tree", "T", "-spa", "index.html", "-stat"}, "-stat cannot be combined with -obfuscate-keys"},
+		{[]string{"-max-mem", "1MB", "-stat", "-comments", "-hash-names"}, "-hash-names cannot be combined with -max-mem"},
+		{[]string{"-hook", "-stat", "-sizes"}, "-hook requires generated accessor functions (e.g. -readonly, -compress, -layout slice or -fs)"},
+		{[]string{"-git-meta"}, "-git-meta requires -stat"},
+		{[]string{"-target", "tinygo", "-readonly"}, "-readonly is not supported by the slice layout"},
+		{[]string{"-emit", "c", "-t", "assets.tmpl"}, "-emit cannot be combined with -t"},
+		{[]string{"-per-dir-output", "-lock", "bindata.lock", "-report", "report.json"}, "-report cannot be combined with -per-dir-output"},
+		{[]string{"-precompressed", "-compress", "gzip"}, "-compress cannot be combined with -precompressed"},
+		{[]string{"-c", config, "-layout", "vars"}, "groups are not supported by the vars layout"},
+		{[]string{"-c", config, "-stat", "-sizes"}, "groups and -o-for cannot be combined with -sizes"},
+		{[]string{"-o-for", "*.gif=gif.go", "-o-copy", "copy.go"}, "groups and -o-for cannot be combined with -o-copy"},
+		{[]string{"-c", config, "-only", "*.go"}, "-only cannot be combined with groups"},
+	}
+	for _, test := range tests {
+		// the same error whatever the order in which the options are checked
+		for i := 0; i < 5; i++ {
+			err := runGenerate(append(test.args, "-o", filepath.Join(t.TempDir(), "out.go"), testdata))
+			if err == nil || err.Error() != test.want {
+				t.Errorf("%v: expected %q, got %v", test.args, test.want, err)
+				break
+			}
+		}
+	}
+
+	if err := runGenerate([]string{"-c", config, testdata}); err == nil || err.Error() != "groups and -o-for require an output file (-o)" {
+		t.Errorf("expected an output file error, got %v", err)
+	}
+	if err := runGenerate([]string{"-only", "*.go", testdata}); err == nil || err.Error() != "-only requires an output file (-o)" {
+		t.Errorf("expected an output file error, got %v", err)
+	}
+
+	// the methods of -receiver call the hook
+	if err := runGenerate([]string{"-hook", "-receiver", "R", "-o", filepath.Join(t.TempDir(), "out.go"), "-r", testdata, testdata}); err != nil {
+		t.Error(err)
+	}
+}
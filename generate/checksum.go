@@ -0,0 +1,65 @@
+package generate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// checksumPrefix starts the trailing comment of a generated file recording
+// the checksum of the rest of the file.
+const checksumPrefix = "// bindata:sha256 "
+
+// AppendChecksum appends to the contents of a generated file a comment
+// recording their SHA-256 checksum, after a blank line.
+func AppendChecksum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return fmt.Appendf(data, "\n%s%x\n", checksumPrefix, sum)
+}
+
+// VerifyChecksum checks that the contents of a generated file match the
+// checksum recorded by their trailing comment, i.e. that the file was not
+// edited since it was generated.
+func VerifyChecksum(data []byte) error {
+	i := bytes.LastIndex(data, []byte("\n"+checksumPrefix))
+	if i < 0 {
+		return errors.New("no checksum comment")
+	}
+	want := strings.TrimSpace(string(data[i+1+len(checksumPrefix):]))
+	sum := sha256.Sum256(data[:i])
+	if hex.EncodeToString(sum[:]) != want {
+		return errors.New("checksum mismatch: edited since it was generated")
+	}
+	return nil
+}
+
+// runVerifyFile runs the verify-file subcommand.
+func runVerifyFile(args []string) error {
+	fs := flag.NewFlagSet("bindata verify-file", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("verify-file: expected generated files")
+	}
+	failed := 0
+	for _, file := range fs.Args() {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			err = VerifyChecksum(data)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("verify-file: %d of %d file(s) failed", failed, fs.NArg())
+	}
+	return nil
+}
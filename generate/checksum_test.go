@@ -0,0 +1,43 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestChecksum tests the detection of generated files edited by hand.
+func TestChecksum(t *testing.T) {
+	data := AppendChecksum([]byte("package main\n"))
+	if err := VerifyChecksum(data); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	edited := append([]byte("// edited\n"), data...)
+	if err := VerifyChecksum(edited); err == nil {
+		t.Error("expected error for edited file")
+	}
+	if err := VerifyChecksum([]byte("package main\n")); err == nil {
+		t.Error("expected error without checksum")
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "bindata.go")
+	if err := runGenerate([]string{"-checksum", "-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runVerifyFile([]string{out}); err != nil {
+		t.Error(err)
+	}
+	if _, err := ParseGenerated(out); err != nil {
+		t.Errorf("cannot parse file with checksum: %v", err)
+	}
+	f, err := os.OpenFile(out, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("// edited\n")
+	f.Close()
+	if err := runVerifyFile([]string{out}); err == nil {
+		t.Error("expected error for edited file")
+	}
+}
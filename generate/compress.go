@@ -0,0 +1,137 @@
+package generate
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strings"
+	"sync"
+)
+
+// incompressible are the extensions of file formats that are already compressed.
+var incompressible = map[string]bool{
+	".7z": true, ".avif": true, ".br": true, ".bz2": true, ".gif": true, ".gz": true,
+	".jar": true, ".jpeg": true, ".jpg": true, ".mp3": true, ".mp4": true, ".ogg": true,
+	".png": true, ".tgz": true, ".webm": true, ".webp": true, ".woff": true, ".woff2": true,
+	".xz": true, ".zip": true, ".zst": true,
+}
+
+const (
+	maxEntropy = 7.5     // bits per byte above which data is considered incompressible
+	minSaving  = 0.1     // fraction of the size a compressed file must save
+	sampleSize = 1 << 16 // number of bytes sampled to estimate the entropy
+)
+
+// codecs are the codecs tried by the auto codec, which picks the one giving
+// the smallest data. Only codecs of the standard library are used, so that the
+// generated code has no dependency.
+var codecs = []string{"gzip", "flate"}
+
+// Compress compresses data with codec if it is worth it.
+// Files in compressed formats, judging by the extension of name, or whose
+// entropy is too high are not compressed, nor are files which compression does
+// not make smaller by at least 10%. With the "auto" codec, all the codecs are
+// tried concurrently and the smallest result is kept. It returns the data to
+// store and the codec used, or an empty codec if the data is stored raw.
+func Compress(codec, name string, data []byte) ([]byte, string, error) {
+	if codec == "" || incompressible[strings.ToLower(path.Ext(name))] {
+		return data, "", nil
+	}
+	sample := data
+	if len(sample) > sampleSize {
+		sample = sample[:sampleSize]
+	}
+	if entropy(sample) > maxEntropy {
+		return data, "", nil
+	}
+
+	tried := []string{codec}
+	if codec == "auto" {
+		tried = codecs
+	}
+	results := make([][]byte, len(tried))
+	errs := make([]error, len(tried))
+	var wg sync.WaitGroup
+	for i, c := range tried {
+		wg.Add(1)
+		go func(i int, c string) {
+			defer wg.Done()
+			results[i], errs[i] = encode(c, data)
+		}(i, c)
+	}
+	wg.Wait()
+	best, stored := "", data
+	for i, c := range tried {
+		if errs[i] != nil {
+			return nil, "", errs[i]
+		}
+		if len(results[i]) < len(stored) {
+			best, stored = c, results[i]
+		}
+	}
+	if float64(len(stored)) > float64(len(data))*(1-minSaving) {
+		return data, "", nil
+	}
+	return stored, best, nil
+}
+
+// encode compresses data with codec, at the best compression level.
+func encode(codec string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch codec {
+	case "gzip":
+		w, _ = gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	case "flate":
+		w, _ = flate.NewWriter(&buf, flate.BestCompression)
+	default:
+		return nil, fmt.Errorf("unknown codec %q", codec)
+	}
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress returns the decompressed data stored with codec,
+// which is empty if the data is stored raw.
+func Decompress(codec string, data []byte) ([]byte, error) {
+	var r io.ReadCloser
+	switch codec {
+	case "":
+		return data, nil
+	case "gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	case "flate":
+		r = flate.NewReader(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unknown codec %q", codec)
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// entropy returns the Shannon entropy of data in bits per byte.
+func entropy(data []byte) float64 {
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	var e float64
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(len(data))
+			e -= p * math.Log2(p)
+		}
+	}
+	return e
+}
@@ -0,0 +1,77 @@
+package generate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestCompress tests the per-file compression decisions.
+func TestCompress(t *testing.T) {
+	text := []byte(strings.Repeat("All work and no play makes Jack a dull boy.\n", 100))
+	random := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	tests := []struct {
+		name  string
+		data  []byte
+		codec string
+	}{
+		{"a.txt", text, "gzip"},
+		{"a.PNG", text, ""},
+		{"random.bin", random, ""},
+		{"tiny.txt", []byte("hi"), ""},
+	}
+	for _, test := range tests {
+		data, codec, err := Compress("gzip", test.name, test.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if codec != test.codec {
+			t.Errorf("%s: expected codec %q, got %q", test.name, test.codec, codec)
+			continue
+		}
+		if codec == "" {
+			if !bytes.Equal(data, test.data) {
+				t.Errorf("%s: raw data modified", test.name)
+			}
+			continue
+		}
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, err := io.ReadAll(r); err != nil || !bytes.Equal(b, test.data) {
+			t.Errorf("%s: round trip failed: %v", test.name, err)
+		}
+	}
+
+	if data, codec, _ := Compress("", "a.txt", text); codec != "" || !bytes.Equal(data, text) {
+		t.Error("data compressed without codec")
+	}
+}
+
+// TestCompressAuto tests the choice of the smallest codec for each file.
+func TestCompressAuto(t *testing.T) {
+	text := []byte(strings.Repeat("All work and no play makes Jack a dull boy.\n", 100))
+	data, codec, err := Compress("auto", "a.txt", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// raw DEFLATE streams are those of gzip without the header and checksum
+	if codec != "flate" {
+		t.Errorf("expected flate, got %q", codec)
+	}
+	if b, err := Decompress(codec, data); err != nil || !bytes.Equal(b, text) {
+		t.Errorf("round trip failed: %v", err)
+	}
+	if data, codec, _ := Compress("auto", "a.png", text); codec != "" || !bytes.Equal(data, text) {
+		t.Error("incompressible format compressed")
+	}
+	if _, err := Decompress("zstd", data); err == nil {
+		t.Error("expected error for unknown codec")
+	}
+}
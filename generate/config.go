@@ -0,0 +1,65 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// A Config is the contents of a JSON configuration file.
+type Config struct {
+	Budget  Size            `json:"budget"`  // maximum total size of the files
+	Budgets map[string]Size `json:"budgets"` // maximum size of the files per directory
+
+	// Groups lists the patterns of the files of each group, indexed by build tag.
+	Groups map[string][]string `json:"groups"`
+
+	// Profiles lists the asset sets selectable with -profile, indexed by name.
+	Profiles map[string]Profile `json:"profiles"`
+
+	// Aliases lists the keys of the files by additional key, e.g. a legacy path,
+	// under which the same contents are accessible.
+	Aliases map[string]string `json:"aliases"`
+
+	// Version is the version of bindata the generation requires, if any,
+	// as recorded by the pin subcommand.
+	Version string `json:"version"`
+}
+
+// A Profile is a named asset set of a configuration, e.g. for dev or prod
+// builds. Its fields apply unless the corresponding flags are set.
+type Profile struct {
+	Inputs   []string `json:"inputs"`   // added to the inputs of the command line
+	Compress string   `json:"compress"` // codec compressing the files (-compress)
+	Output   string   `json:"output"`   // output file (-o)
+}
+
+// Profile returns the named profile of the configuration.
+func (c *Config) Profile(name string) (Profile, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return p, fmt.Errorf("unknown profile %q (have %s)", name, strings.Join(slices.Sorted(maps.Keys(c.Profiles)), ", "))
+	}
+	return p, nil
+}
+
+// LoadConfig reads the configuration file at path.
+// Unknown fields are reported as errors.
+func LoadConfig(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var c Config
+	dec := json.NewDecoder(file)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &c, nil
+}
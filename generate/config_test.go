@@ -0,0 +1,113 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfig tests the parsing of configuration files.
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bindata.json")
+	if err := os.WriteFile(path, []byte(`{"budget": "1MB", "budgets": {"img": 2048}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Budget != 1e6 || c.Budgets["img"] != 2048 {
+		t.Errorf("unexpected config %+v", c)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"bugdet": "1MB"}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+// TestProfiles tests selecting the inputs, compression and output file of a
+// profile of the configuration.
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "bindata.json")
+	c := `{"profiles": {
+		"dev": {"inputs": ["` + filepath.ToSlash(filepath.Join(testdata, "play", "bytes")) + `"], "output": "` + filepath.ToSlash(filepath.Join(dir, "dev.go")) + `"},
+		"prod": {"inputs": ["` + filepath.ToSlash(filepath.Join(testdata, "empty")) + `"], "compress": "gzip", "output": "` + filepath.ToSlash(filepath.Join(dir, "prod.go")) + `"}
+	}}`
+	if err := os.WriteFile(config, []byte(c), 0666); err != nil {
+		t.Fatal(err)
+	}
+	for profile, want := range map[string]string{"dev": "play/bytes/11", "prod": "bindataRead"} {
+		if err := runGenerate([]string{"-c", config, "-profile", profile, "-r", testdata}); err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, profile+".go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%s: expected %q in:\n%s", profile, want, b)
+		}
+	}
+
+	// the flags override the profile
+	out := filepath.Join(dir, "out.go")
+	if err := runGenerate([]string{"-c", config, "-profile", "prod", "-compress", "none", "-o", out, "-r", testdata}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(out); err != nil || strings.Contains(string(b), "bindataRead") {
+		t.Errorf("unexpected output %v:\n%s", err, b)
+	}
+	if err := runGenerate([]string{"-c", config, "-profile", "staging"}); err == nil || !strings.Contains(err.Error(), "have dev, prod") {
+		t.Errorf("expected unknown profile, got %v", err)
+	}
+}
+
+// TestAliases tests the files accessible under aliases declared in the
+// configuration.
+func TestAliases(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "bindata.json")
+	if err := os.WriteFile(config, []byte(`{"aliases": {"favicon.ico": "play/bytes/11", "old/hello.go": "play/hello.go"}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	for _, name := range []string{"favicon.ico", "play/bytes/11", "old/hello.go"} {
+		data, ok := bindataData(name)
+		fmt.Println(name, len(data), ok)
+	}
+}
+`
+	const ref = "favicon.ico 11 true\nplay/bytes/11 11 true\nold/hello.go 74 true\n"
+	for _, flags := range [][]string{{"-fs"}, {"-fs", "-compress", "gzip"}, {"-fs", "-s", "-embed-over", "11B"}} {
+		args := append(flags, "-c", config, "-r", testdata, filepath.Join(testdata, "play"))
+		if out := runGenerated(t, main, args...); out != ref {
+			t.Errorf("%v: unexpected output:\n%s", flags, out)
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "out.go")
+	if err := runGenerate([]string{"-c", config, "-layout", "slice", "-o", out, testdata}); err == nil {
+		t.Error("expected error for -layout slice")
+	}
+	if err := runGenerate([]string{"-c", config, "-target", "tinygo", "-o", out, testdata}); err == nil || !strings.Contains(err.Error(), "-target tinygo") {
+		t.Errorf("expected error for -target tinygo, got %v", err)
+	}
+	if err := runGenerate([]string{"-c", config, "-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err == nil || !strings.Contains(err.Error(), "no file") {
+		t.Errorf("expected error for missing file, got %v", err)
+	}
+	if err := os.WriteFile(config, []byte(`{"aliases": {"play/bytes/12": "play/bytes/11"}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate([]string{"-c", config, "-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err == nil {
+		t.Error("expected error for alias of an existing key")
+	}
+}
@@ -0,0 +1,104 @@
+package generate
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+)
+
+// A Change is the difference of a file between two generations.
+// The sizes are those of the decompressed contents, -1 if the file is absent.
+type Change struct {
+	Key     string
+	OldSize int
+	NewSize int
+}
+
+// Diff returns the files added, removed or modified between old and new, sorted by key.
+func Diff(old, new *Generated) ([]Change, error) {
+	keys := make(map[string]bool)
+	for key := range old.Data {
+		keys[key] = true
+	}
+	for key := range new.Data {
+		keys[key] = true
+	}
+	var changes []Change
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		c := Change{Key: key, OldSize: -1, NewSize: -1}
+		var a, b []byte
+		var err error
+		if _, ok := old.Data[key]; ok {
+			if a, err = old.Read(key); err != nil {
+				return nil, err
+			}
+			c.OldSize = len(a)
+		}
+		if _, ok := new.Data[key]; ok {
+			if b, err = new.Read(key); err != nil {
+				return nil, err
+			}
+			c.NewSize = len(b)
+		}
+		if c.OldSize < 0 || c.NewSize < 0 || !bytes.Equal(a, b) {
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+// writeDiff writes a report of the changes followed by a summary.
+func writeDiff(w io.Writer, changes []Change) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	var added, removed, modified, delta int
+	for _, c := range changes {
+		switch {
+		case c.OldSize < 0:
+			added++
+			delta += c.NewSize
+			fmt.Fprintf(tw, "+\t%s\t%d bytes\n", c.Key, c.NewSize)
+		case c.NewSize < 0:
+			removed++
+			delta -= c.OldSize
+			fmt.Fprintf(tw, "-\t%s\t%d bytes\n", c.Key, c.OldSize)
+		default:
+			modified++
+			delta += c.NewSize - c.OldSize
+			fmt.Fprintf(tw, "~\t%s\t%d -> %d bytes (%+d)\n", c.Key, c.OldSize, c.NewSize, c.NewSize-c.OldSize)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d added, %d removed, %d changed, %+d bytes\n", added, removed, modified, delta)
+	return err
+}
+
+// runDiff reports the differences between the files of two generated files.
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("bindata diff", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("diff: expected two generated files")
+	}
+	old, err := ParseGenerated(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	new, err := ParseGenerated(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	changes, err := Diff(old, new)
+	if err != nil {
+		return err
+	}
+	return writeDiff(os.Stdout, changes)
+}
@@ -0,0 +1,66 @@
+package generate
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestDiff tests the comparison of generated files.
+func TestDiff(t *testing.T) {
+	old := &Generated{Data: map[string][]byte{
+		"same.txt":    []byte("same"),
+		"changed.txt": []byte("old"),
+		"removed.txt": []byte("removed"),
+	}}
+	new := &Generated{Data: map[string][]byte{
+		"same.txt":    []byte("same"),
+		"changed.txt": []byte("new contents"),
+		"added.txt":   []byte("added"),
+	}}
+	changes, err := Diff(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{
+		{"added.txt", -1, 5},
+		{"changed.txt", 3, 12},
+		{"removed.txt", 7, -1},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected %v, got %v", want, changes)
+	}
+
+	var buf bytes.Buffer
+	if err := writeDiff(&buf, changes); err != nil {
+		t.Fatal(err)
+	}
+	const report = `+  added.txt    5 bytes
+~  changed.txt  3 -> 12 bytes (+9)
+-  removed.txt  7 bytes
+1 added, 1 removed, 1 changed, +7 bytes
+`
+	if buf.String() != report {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+
+	// the same files in another layout
+	dir := t.TempDir()
+	for _, layout := range []string{"map", "blob"} {
+		if err := runGenerate([]string{"-layout", layout, "-o", filepath.Join(dir, layout+".go"), "-r", testdata, testdata}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old, err = ParseGenerated(filepath.Join(dir, "map.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err = ParseGenerated(filepath.Join(dir, "blob.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes, err := Diff(old, new); err != nil || len(changes) != 0 {
+		t.Errorf("expected no changes, got %v (%v)", changes, err)
+	}
+}
@@ -0,0 +1,73 @@
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// With -embed-over, the files larger than a threshold are not written as
+// literals, which are slow to compile, but stored in a directory next to the
+// output file and embedded with go:embed. The generated code adds them to the
+// map on initialization, so that the accessors are the same for all files.
+
+// EmbedDir returns the name of the directory of the files embedded with
+// go:embed for the output file out, e.g. "bindata_files" for "bindata.go".
+func EmbedDir(out string) string {
+	return strings.TrimSuffix(filepath.Base(out), ".go") + "_files"
+}
+
+// EmbeddedName returns the name of a file embedded with go:embed: the SHA-256
+// checksum of its contents, so that identical files are stored once and
+// unchanged files are not written again.
+func EmbeddedName(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteEmbedded writes the files to embed with go:embed, indexed by name, to
+// the directory dir with write, and removes the files of previous generations
+// which are no longer embedded, along with dir if it ends up empty. Only the
+// files named by EmbeddedName are removed.
+func WriteEmbedded(dir string, files map[string][]byte, write func(path string, write func(io.Writer) error) error) error {
+	if len(files) > 0 {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	for name, data := range files {
+		if err := write(filepath.Join(dir, name), func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if _, ok := files[e.Name()]; ok || !e.Type().IsRegular() || !isEmbeddedName(e.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	if len(files) == 0 {
+		os.Remove(dir) // only if empty
+	}
+	return nil
+}
+
+// isEmbeddedName reports whether name is a name returned by EmbeddedName.
+func isEmbeddedName(name string) bool {
+	b, err := hex.DecodeString(name)
+	return err == nil && len(b) == sha256.Size && name == strings.ToLower(name)
+}
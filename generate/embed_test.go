@@ -0,0 +1,65 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestEmbedOver tests the files embedded with go:embed beyond a size.
+func TestEmbedOver(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	for _, name := range []string{"play/bytes/11", "play/bytes/12", "play/hello.go"} {
+		data, err := bindataRead(name)
+		fmt.Println(name, len(data), err)
+	}
+}
+`
+	const ref = "play/bytes/11 11 <nil>\nplay/bytes/12 12 <nil>\nplay/hello.go 74 <nil>\n"
+	for _, flags := range [][]string{nil, {"-s"}} {
+		args := append(flags, "-embed-over", "11B", "-compress", "gzip", "-r", testdata, filepath.Join(testdata, "play"))
+		if out := runGenerated(t, main, args...); out != ref {
+			t.Errorf("%v: unexpected output:\n%s", flags, out)
+		}
+	}
+
+	// the files no longer embedded are removed
+	dir := t.TempDir()
+	out := filepath.Join(dir, "assets.go")
+	embedDir := filepath.Join(dir, "assets_files")
+	args := []string{"-o", out, "-r", testdata, filepath.Join(testdata, "play")}
+	if err := runGenerate(append([]string{"-embed-over", "12B"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := os.ReadDir(embedDir); err != nil || len(entries) != 2 {
+		t.Errorf("expected 2 files embedded, got %v (%v)", entries, err)
+	}
+	if err := os.WriteFile(filepath.Join(embedDir, "README"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate(append([]string{"-embed-over", "1KB"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := os.ReadDir(embedDir); err != nil || len(entries) != 1 || entries[0].Name() != "README" {
+		t.Errorf("expected only README left, got %v (%v)", entries, err)
+	}
+
+	// the embedded files are not inputs
+	if err := runGenerate([]string{"-embed-over", "12B", "-o", out, "-r", dir, dir}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate([]string{"-embed-over", "1KB", "-layout", "slice", "-o", out, testdata}); err == nil {
+		t.Error("expected error for -layout slice")
+	}
+	if err := runGenerate([]string{"-embed-over", "1KB", "-target", "tinygo", "-o", out, testdata}); err == nil || !strings.Contains(err.Error(), "-target tinygo") {
+		t.Errorf("expected error for -target tinygo, got %v", err)
+	}
+	if err := runGenerate([]string{"-embed-over", "1KB", testdata}); err == nil {
+		t.Error("expected error without output file")
+	}
+}
@@ -0,0 +1,146 @@
+package generate
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// emitters write the files in languages other than Go (-emit), given the
+// comment marking generated files, the name of the data and the sorted keys.
+var emitters = map[string]func(w io.Writer, generated, name string, assets map[string]*Asset, keys []string) error{
+	"c":    EmitC,
+	"json": EmitJSON,
+}
+
+// EmitC writes the files as a C header: an array of unsigned char per file
+// and a table of their names, data and sizes named name, ended by an entry
+// whose name is NULL, along with the number of files (name_count).
+func EmitC(w io.Writer, generated, name string, assets map[string]*Asset, keys []string) error {
+	bw := bufio.NewWriter(w)
+	guard := strings.ToUpper(name) + "_H"
+	if generated != "" {
+		fmt.Fprintf(bw, "%s\n\n", generated)
+	}
+	fmt.Fprintf(bw, "#ifndef %s\n#define %s\n\n#include <stddef.h>\n", guard, guard)
+	for i, key := range keys {
+		data := assets[key].Data
+		fmt.Fprintf(bw, "\n/* %s */\nstatic const unsigned char %s_%d[] = {", cComment(filepath.ToSlash(key)), name, i)
+		if len(data) == 0 {
+			bw.WriteString("0};\n") // empty initializers are invalid in C
+			continue
+		}
+		for j, b := range data {
+			if j%12 == 0 {
+				bw.WriteString("\n\t")
+			} else {
+				bw.WriteString(" ")
+			}
+			fmt.Fprintf(bw, "0x%02x,", b)
+		}
+		bw.WriteString("\n};\n")
+	}
+	fmt.Fprintf(bw, "\nstatic const struct {\n\tconst char *name;\n\tconst unsigned char *data;\n\tsize_t size;\n} %s[] = {\n", name)
+	for i, key := range keys {
+		fmt.Fprintf(bw, "\t{%s, %s_%d, %d},\n", cString(filepath.ToSlash(key)), name, i, len(assets[key].Data))
+	}
+	fmt.Fprintf(bw, "\t{NULL, NULL, 0},\n};\n\nstatic const size_t %s_count = %d;\n\n#endif /* %s */\n", name, len(keys), guard)
+	return bw.Flush()
+}
+
+// cString returns s as a C string literal, with the bytes that are not
+// printable ASCII characters escaped in octal.
+func cString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c >= 0x7f || c == '?': // '?' to avoid trigraphs
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// cComment returns s safe to insert in a C comment.
+func cComment(s string) string {
+	return strings.ReplaceAll(s, "*/", "*\\/")
+}
+
+// EmitJSON writes the files as a JSON object mapping their keys to their
+// contents encoded in base64, under the name of the data:
+//
+//	{"bindata": {"index.html": "PGh0bWw+..."}}
+//
+// It fails if a key is not valid UTF-8, which JSON cannot represent.
+func EmitJSON(w io.Writer, generated, name string, assets map[string]*Asset, keys []string) error {
+	files := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if !utf8.ValidString(key) {
+			return fmt.Errorf("cannot represent %q in JSON: invalid UTF-8", key)
+		}
+		files[filepath.ToSlash(key)] = assets[key].Data
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(map[string]map[string][]byte{name: files})
+}
+
+// templateFuncs are the functions available to the custom templates (-t)
+// besides those of text/template.
+var templateFuncs = template.FuncMap{
+	"camelcase": identifier,
+	"sha256": func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	},
+	"base64": base64.StdEncoding.EncodeToString,
+}
+
+// TemplateData is the data of the custom templates (-t).
+type TemplateData struct {
+	Generated string // comment marking generated files, if any
+	Name      string // name of the data (-m)
+	Files     []TemplateFile
+}
+
+// A TemplateFile is a file of the data of a custom template.
+type TemplateFile struct {
+	Key  string
+	Data []byte
+}
+
+// ParseTemplate parses a custom template from a file, with templateFuncs.
+func ParseTemplate(file string) (*template.Template, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(filepath.Base(file)).Funcs(templateFuncs).Parse(string(b))
+}
+
+// TemplateEmitter returns an emitter writing the files with a custom template.
+func TemplateEmitter(t *template.Template) func(w io.Writer, generated, name string, assets map[string]*Asset, keys []string) error {
+	return func(w io.Writer, generated, name string, assets map[string]*Asset, keys []string) error {
+		data := TemplateData{Generated: generated, Name: name, Files: make([]TemplateFile, 0, len(keys))}
+		for _, key := range keys {
+			data.Files = append(data.Files, TemplateFile{filepath.ToSlash(key), assets[key].Data})
+		}
+		return t.Execute(w, data)
+	}
+}
@@ -0,0 +1,121 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestEmitC tests the C header, compiling it if a C compiler is available.
+func TestEmitC(t *testing.T) {
+	assets := map[string]*Asset{
+		"a.txt":        {Data: []byte("hello")},
+		"empty":        {Data: nil},
+		`we"ird?*/.md`: {Data: []byte{0, 255}},
+	}
+	keys := []string{"a.txt", "empty", `we"ird?*/.md`}
+	var buf bytes.Buffer
+	if err := EmitC(&buf, defaultGenerated, "assets", assets, keys); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"static const unsigned char assets_0[] = {\n\t0x68, 0x65, 0x6c, 0x6c, 0x6f,\n};",
+		"static const unsigned char assets_1[] = {0};",
+		`{"we\"ird\077*/.md", assets_2, 2},`,
+		"static const size_t assets_count = 3;",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, buf.String())
+		}
+	}
+
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		return
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "assets.h"), buf.Bytes(), 0666); err != nil {
+		t.Fatal(err)
+	}
+	main := "#include <stdio.h>\n#include \"assets.h\"\nint main(void) { fwrite(assets[0].data, 1, assets[0].size, stdout); return assets[3].name != NULL; }\n"
+	if err := os.WriteFile(filepath.Join(dir, "main.c"), []byte(main), 0666); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(cc, "-std=c99", "-Wall", "-Werror", "-c", "main.c")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("%v\n%s", err, out)
+	}
+}
+
+// TestEmitJSON tests the JSON bundle.
+func TestEmitJSON(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "bundle.json")
+	if err := runGenerate([]string{"-emit", "json", "-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bundle map[string]map[string][]byte
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(bundle["bindata"]["play/bytes/11"]); got != "10+1 bytes!" || len(bundle["bindata"]) != 3 {
+		t.Errorf("unexpected bundle %v", bundle)
+	}
+	if err := runGenerate([]string{"-emit", "json", "-compress", "gzip", testdata}); err == nil {
+		t.Error("expected error with -compress")
+	}
+}
+
+// TestEmitTemplate tests the custom templates and their functions.
+func TestEmitTemplate(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "assets.tmpl")
+	const text = "{{.Generated}}\n{{range .Files}}{{camelcase .Key}} {{sha256 .Data | printf \"%.8s\"}} {{base64 .Data}}\n{{end}}"
+	if err := os.WriteFile(tmpl, []byte(text), 0666); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "assets.txt")
+	args := []string{"-t", tmpl, "-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}
+	for i := 0; i < 2; i++ { // the output is overwritten the second time
+		if err := runGenerate(args); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := defaultGenerated + "\nPlayBytes11 eab36655 MTArMSBieXRlcyE=\n"
+	if !strings.HasPrefix(string(b), want) || strings.Count(string(b), "\n") != 4 {
+		t.Errorf("unexpected output:\n%s", b)
+	}
+	if err := runGenerate(append([]string{"-emit", "c"}, args...)); err == nil {
+		t.Error("expected error with -emit")
+	}
+	if err := runGenerate(append([]string{"-compress", "gzip"}, args...)); err == nil {
+		t.Error("expected error with -compress")
+	}
+
+	// the output of a template which does not print .Generated could not be
+	// overwritten
+	if err := os.WriteFile(tmpl, []byte("{{.Name}}\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate(args); err == nil || !strings.Contains(err.Error(), "does not print .Generated") {
+		t.Errorf("expected an error without .Generated, got %v", err)
+	}
+	args = append([]string{"-generated", ""}, args...)
+	for i := 0; i < 2; i++ {
+		if err := runGenerate(args); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package generate
+
+import (
+	"io/fs"
+	"time"
+)
+
+// A Filter excludes the files found in directories by size and age,
+// e.g. stale or oversized artifacts of shared asset directories, and limits
+// the depth of the walk. The files given explicitly are never excluded.
+type Filter struct {
+	MinSize  Size          // minimum size of the files, 0 for no limit
+	MaxSize  Size          // maximum size of the files, 0 for no limit
+	MaxAge   time.Duration // maximum time since the last modification, 0 for no limit
+	MaxDepth int           // maximum number of directory levels walked, 0 for no limit
+}
+
+// Excluded reports whether the file described by fi is excluded at time now.
+// Directories and non-regular files are never excluded.
+func (f Filter) Excluded(fi fs.FileInfo, now time.Time) bool {
+	if !fi.Mode().IsRegular() {
+		return false
+	}
+	size := Size(fi.Size())
+	return size < f.MinSize ||
+		f.MaxSize > 0 && size > f.MaxSize ||
+		f.MaxAge > 0 && now.Sub(fi.ModTime()) > f.MaxAge
+}
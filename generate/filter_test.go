@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFilter tests the exclusion of the files of directories by size and age.
+func TestFilter(t *testing.T) {
+	gen := newGenerator(context.Background(), nil)
+	dir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	for name, size := range map[string]int{"tiny": 1, "small": 10, "big": 1000, "old": 10} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if name == "old" {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		filter Filter
+		path   string
+		want   string
+	}{
+		{Filter{}, dir, "big old small tiny"},
+		{Filter{MinSize: 5, MaxSize: 100}, dir, "old small"},
+		{Filter{MaxAge: 24 * time.Hour}, dir, "big small tiny"},
+		{Filter{MinSize: 5}, filepath.Join(dir, "tiny"), "tiny"}, // explicit files are kept
+	}
+	for _, test := range tests {
+		gen.assets, gen.filter = make(map[string]*Asset), test.filter
+		if err := gen.AddPath(test.path, filepath.Dir(test.path)); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for key := range gen.assets {
+			got = append(got, filepath.Base(key))
+		}
+		sort.Strings(got)
+		if strings.Join(got, " ") != test.want {
+			t.Errorf("%+v: expected %s, got %s", test.filter, test.want, got)
+		}
+	}
+}
+
+// TestWalkLimits tests the maximum depth and the cycles of directory walks.
+func TestWalkLimits(t *testing.T) {
+	gen := newGenerator(context.Background(), nil)
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/deep/c.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(dir, filepath.Join(dir, "sub", "loop")); err != nil {
+		t.Skip(err)
+	}
+
+	for depth, want := range map[int]int{0: 3, 1: 1, 2: 2} {
+		gen.assets, gen.filter = make(map[string]*Asset), Filter{MaxDepth: depth}
+		if err := gen.AddPath(dir, dir); err != nil {
+			t.Fatal(err)
+		}
+		if len(gen.assets) != want {
+			t.Errorf("depth %d: expected %d files, got %v", depth, want, gen.assets)
+		}
+	}
+	gen.assets, gen.filter, gen.strict = make(map[string]*Asset), Filter{}, true
+	if err := gen.AddPath(dir, dir); err == nil {
+		t.Error("expected error for cycle with -strict")
+	}
+}
@@ -0,0 +1,254 @@
+package generate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Default layout of the formatters.
+const (
+	DefaultByteColumns   = 12   // bytes per line of a ByteSliceFormatter
+	DefaultStringColumns = 16   // bytes per line of a StringFormatter
+	DefaultTab           = "\t" // indentation of the lines of bytes within the value
+)
+
+// A formatter pretty prints the bytes read from its io.Reader as a Go value,
+// through fmt or streamed to an io.Writer.
+type formatter interface {
+	fmt.Formatter
+	io.WriterTo
+}
+
+// A ByteSliceFormatter is a byte slice pretty printing io.Reader.
+// The zero values of its layout fields select the defaults.
+type ByteSliceFormatter struct {
+	io.Reader
+	Columns   int    // number of bytes per line
+	Indent    string // indentation of the line where the value starts, e.g. a tab in a map literal
+	Tab       string // additional indentation of the lines of bytes
+	Uppercase bool   // print hexadecimal digits in upper case
+}
+
+// Format pretty prints the bytes read from the ByteSliceFormatter.
+func (f ByteSliceFormatter) Format(s fmt.State, c rune) {
+	f.WriteTo(s)
+}
+
+// WriteTo pretty prints the bytes read from the ByteSliceFormatter to w
+// through a bounded buffer, so that large files can be streamed.
+// It returns the number of bytes written and the first error reading or
+// writing, if any.
+func (f ByteSliceFormatter) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	buf := bufio.NewReader(f)
+	cols, tab := lineLayout(f.Columns, DefaultByteColumns, f.Tab)
+	format := "%#02x,"
+	if f.Uppercase {
+		format = "0x%02X,"
+	}
+
+	b, err := buf.ReadByte()
+	if err != nil {
+		bw.WriteString("[]byte{}") // as formatted by gofmt
+		return flush(bw, cw, err)
+	}
+	bw.WriteString("[]byte{")
+	for i := 0; err == nil; i++ {
+		if i%cols == 0 {
+			fmt.Fprintf(bw, "\n%s%s", f.Indent, tab)
+		} else {
+			bw.WriteString(" ")
+		}
+		fmt.Fprintf(bw, format, b)
+		b, err = buf.ReadByte()
+	}
+	fmt.Fprintf(bw, "\n%s}", f.Indent)
+	return flush(bw, cw, err)
+}
+
+// A StringFormatter is a string pretty printing io.Reader.
+// The zero values of its layout fields select the defaults.
+type StringFormatter struct {
+	io.Reader
+	Columns   int    // number of bytes per line
+	Indent    string // indentation of the line where the value starts, e.g. a tab in a map literal
+	Tab       string // additional indentation of the lines of bytes
+	Uppercase bool   // print hexadecimal digits in upper case
+}
+
+// Format pretty prints the bytes read from the StringFormatter.
+func (f StringFormatter) Format(s fmt.State, c rune) {
+	f.WriteTo(s)
+}
+
+// WriteTo pretty prints the bytes read from the StringFormatter to w
+// through a bounded buffer, so that large files can be streamed.
+// It returns the number of bytes written and the first error reading or
+// writing, if any.
+func (f StringFormatter) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	buf := bufio.NewReader(f)
+	cols, tab := lineLayout(f.Columns, DefaultStringColumns, f.Tab)
+	format := "\\x%02x"
+	if f.Uppercase {
+		format = "\\x%02X"
+	}
+
+	bw.WriteString(`"`)
+	b, err := buf.ReadByte()
+	for i := 0; err == nil; i++ {
+		if i%cols == 0 {
+			fmt.Fprintf(bw, "\" +\n%s%s\"", f.Indent, tab)
+		}
+		fmt.Fprintf(bw, format, b)
+		b, err = buf.ReadByte()
+	}
+	bw.WriteString(`"`)
+	return flush(bw, cw, err)
+}
+
+// lineLayout returns the number of bytes per line and the indentation of the
+// lines of a formatter, cols and tab unless they are zero.
+func lineLayout(cols, defaultCols int, tab string) (int, string) {
+	if cols <= 0 {
+		cols = defaultCols
+	}
+	if tab == "" {
+		tab = DefaultTab
+	}
+	return cols, tab
+}
+
+// flush flushes the buffer of a formatter at the end of its input, returning
+// the number of bytes written and the error reading the input unless it is
+// io.EOF, or the error flushing.
+func flush(bw *bufio.Writer, cw *countingWriter, err error) (int64, error) {
+	if ferr := bw.Flush(); ferr != nil {
+		return cw.n, ferr
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return cw.n, err
+}
+
+// A countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes to the underlying writer.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// An entry is an entry of a map literal of the generated code, printed on a
+// single line as "{{.Key}}{{.Value}}".
+type entry struct {
+	Name  string
+	Key   string // quoted name followed by a colon and the padding aligning the values
+	Value any
+}
+
+// aligned returns the entries of m, a map with string keys, sorted by key and
+// padded as gofmt aligns the values of consecutive entries. The comments of
+// comments are printed on their own line before the entries of their keys.
+// The entries of nil values, e.g. files embedded with go:embed, are left out.
+func aligned(m any, comments map[string]string) []entry {
+	v := reflect.ValueOf(m)
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		if e := v.MapIndex(k); e.Kind() != reflect.Interface || !e.IsNil() {
+			keys = append(keys, k.String())
+		}
+	}
+	sort.Strings(keys)
+
+	entries := make([]entry, len(keys))
+	widths := make([]int, len(keys))
+	// gofmt starts a new section of aligned entries around the entries spanning
+	// several lines, after a comment, or when the size of a long key is off
+	// from the geometric mean of the sizes of the keys of the section by a
+	// factor of 2.5 (see go/printer)
+	start, width := 0, 0
+	log2sum, count := 0.0, 0
+	prev := 0 // size of the previous key, 0 if its entry spans several lines
+	for i, key := range keys {
+		quoted := fmt.Sprintf("%#v", key)
+		entries[i] = entry{key, quoted + ":", v.MapIndex(reflect.ValueOf(key)).Interface()}
+		widths[i] = utf8.RuneCountInString(entries[i].Key)
+
+		size := len(quoted)
+		if !oneLine(entries[i].Value) {
+			size = 0
+		}
+		if i > 0 {
+			section := size == 0 || prev == 0 || comments[key] != ""
+			if !section && (prev > 40 || size > 40) {
+				ratio := float64(size) / exp2ish(log2sum/float64(count))
+				section = 2.5*ratio <= 1 || 2.5 <= ratio
+			}
+			if section {
+				pad(entries[start:i], widths[start:i], width)
+				start, width = i, 0
+				log2sum, count = 0, 0
+			}
+		}
+		width = max(width, widths[i])
+		if size > 0 {
+			log2sum += log2ish(float64(size))
+			count++
+		}
+		prev = size
+	}
+	pad(entries[start:], widths[start:], width)
+	return entries
+}
+
+// oneLine reports whether v is printed on a single line: the formatters print
+// the contents of the files on several lines, unless they are empty.
+func oneLine(v any) bool {
+	var r io.Reader
+	switch f := v.(type) {
+	case ByteSliceFormatter:
+		r = f.Reader
+	case StringFormatter:
+		r = f.Reader
+	case streamRef:
+		return false
+	default:
+		return true
+	}
+	l, ok := r.(interface{ Len() int })
+	return ok && l.Len() == 0
+}
+
+// pad pads the keys of entries to width, plus the space before the values.
+func pad(entries []entry, widths []int, width int) {
+	for i := range entries {
+		entries[i].Key += strings.Repeat(" ", width-widths[i]+1)
+	}
+}
+
+// log2ish and exp2ish are the approximations of log2 and 2**x used by
+// go/printer to compare the sizes of the keys.
+func log2ish(x float64) float64 {
+	f, e := math.Frexp(x)
+	return float64(e) + 2*(f-1)
+}
+
+func exp2ish(x float64) float64 {
+	n := math.Floor(x)
+	return math.Ldexp(1+x-n, int(n))
+}
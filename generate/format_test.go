@@ -0,0 +1,89 @@
+package generate
+
+import (
+	"fmt"
+	"go/format"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestFormatters tests the layout of the formatters.
+func TestFormatters(t *testing.T) {
+	data := "\x01\xab\xcd\xef\x10"
+	tests := []struct {
+		f   fmt.Formatter
+		ref string
+	}{
+		{
+			ByteSliceFormatter{Reader: strings.NewReader(data)},
+			"[]byte{\n\t0x01, 0xab, 0xcd, 0xef, 0x10,\n}",
+		},
+		{
+			ByteSliceFormatter{Reader: strings.NewReader(data + data + data), Indent: "\t"},
+			"[]byte{\n\t\t0x01, 0xab, 0xcd, 0xef, 0x10, 0x01, 0xab, 0xcd, 0xef, 0x10, 0x01, 0xab,\n\t\t0xcd, 0xef, 0x10,\n\t}",
+		},
+		{
+			ByteSliceFormatter{Reader: strings.NewReader(""), Indent: "\t"},
+			"[]byte{}",
+		},
+		{
+			StringFormatter{Reader: strings.NewReader(data), Indent: "\t"},
+			"\"\" +\n\t\t\"\\x01\\xab\\xcd\\xef\\x10\"",
+		},
+		{
+			StringFormatter{Reader: strings.NewReader(data + data + data + data)},
+			"\"\" +\n\t\"\\x01\\xab\\xcd\\xef\\x10\\x01\\xab\\xcd\\xef\\x10\\x01\\xab\\xcd\\xef\\x10\\x01\" +\n\t\"\\xab\\xcd\\xef\\x10\"",
+		},
+		{
+			ByteSliceFormatter{Reader: strings.NewReader(data), Columns: 2, Indent: "\t", Tab: "  ", Uppercase: true},
+			"[]byte{\n\t  0x01, 0xAB,\n\t  0xCD, 0xEF,\n\t  0x10,\n\t}",
+		},
+		{
+			StringFormatter{Reader: strings.NewReader(data), Columns: 3, Tab: "    ", Uppercase: true},
+			"\"\" +\n    \"\\x01\\xAB\\xCD\" +\n    \"\\xEF\\x10\"",
+		},
+	}
+	for i, test := range tests {
+		if got := fmt.Sprintf("%v", test.f); got != test.ref {
+			t.Errorf("%d: expected\n%s\ngot\n%s", i, test.ref, got)
+		}
+	}
+}
+
+// TestAligned tests the alignment of map entries against gofmt.
+func TestAligned(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		m, comments := make(map[string]int), make(map[string]string)
+		for j := r.Intn(12); j >= 0; j-- {
+			key := strings.Repeat("a", 1+r.Intn(8))
+			if r.Intn(3) == 0 {
+				key = strings.Repeat("b", 30+r.Intn(150))
+			}
+			if r.Intn(5) == 0 {
+				key += "é\t"
+			}
+			m[key] = j
+			if r.Intn(6) == 0 {
+				comments[key] = "comment"
+			}
+		}
+		var b strings.Builder
+		b.WriteString("package p\n\nvar m = map[string]int{")
+		for _, e := range aligned(m, comments) {
+			if c := comments[e.Name]; c != "" {
+				fmt.Fprintf(&b, "\n\t// %s", c)
+			}
+			fmt.Fprintf(&b, "\n\t%s%d,", e.Key, e.Value)
+		}
+		b.WriteString("\n}\n")
+		got, err := format.Source([]byte(b.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != b.String() {
+			t.Fatalf("expected\n%s\ngot\n%s", got, b.String())
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package generate implements the bindata command, which generates Go code
+// embedding files, so that it can run from other programs as well: Generate
+// takes the arguments of a command line, documented with the command.
+package generate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+	"time"
+)
+
+// A Progress reports a file added to the assets during a generation.
+type Progress struct {
+	Key   string // key of the file
+	Size  int    // size of the file once transformed
+	Files int    // number of files added so far
+
+	Read time.Duration // time spent opening and reading the file
+}
+
+// A generator holds the state of a generation, so that several generations
+// can run concurrently in one process, each with its own configuration.
+type generator struct {
+	ctx        context.Context // checked while walking directories and reading files
+	onProgress func(Progress)  // called after each file added to the assets, if not nil
+	log        logger          // diagnostics: warnings and reports
+	quiet      bool            // output to stdout without diagnostics (-o -)
+
+	vars       templateVars
+	assets     map[string]*Asset // files to embed indexed by key
+	transforms Transforms        // applied to the files before they are embedded
+	inputs     []string          // paths given on the command line
+	sources    []Source          // files given to Generate
+	filter     Filter            // applied when walking directories
+	routes     Routes            // routes of the command line
+
+	// names of the files listing the paths to ignore
+	// in the directory containing them and its subdirectories
+	ignoreFiles []string
+
+	// policy applied when two sources have the same key: "first" keeps
+	// the first one, "last" keeps the last one and "error" fails
+	onCollision string
+
+	only Patterns // restricts the files added to those matching its patterns, if any
+
+	// strict makes unreadable inputs collected and reported together
+	// instead of stopping the generation at the first one
+	strict      bool
+	inputErrors InputErrors // input errors collected in strict mode
+
+	transformCache string // directory caching the outputs of the transforms, if any
+
+	// layout of the lines of bytes of the files (-columns, -tab, -uppercase),
+	// the zero values selecting the defaults of the formatters
+	columns   int
+	tab       string
+	uppercase bool
+
+	eol      string // line endings of the text files, lf or crlf, empty to keep them
+	stripBOM bool   // strip the UTF-8 byte order mark of the text files
+
+	// maxMem caps the size of the contents held in memory, if not zero:
+	// the files beyond it are streamed from their source files (-max-mem)
+	maxMem  Size
+	memUsed Size // size of the contents held in memory
+
+	cache *fileCache // contents of the files read by previous generations, if any
+
+	tmpl      *template.Template // template of the generated file
+	groupTmpl *template.Template // template of the files of the groups
+}
+
+// newGenerator returns a generator checking ctx and calling progress, if not
+// nil, after each file added to the assets.
+func newGenerator(ctx context.Context, progress func(Progress)) *generator {
+	return &generator{
+		ctx:         ctx,
+		onProgress:  progress,
+		log:         logger{w: os.Stderr},
+		assets:      make(map[string]*Asset),
+		ignoreFiles: []string{".bindataignore"},
+		onCollision: "error",
+		tmpl:        template.Must(template.New("bindata").Funcs(template.FuncMap{"aligned": aligned}).Parse(tmplText)),
+		groupTmpl:   template.Must(template.New("group").Parse(groupTmplText)),
+	}
+}
+
+// A ctxReader is a reader failing once its context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read reads from the underlying reader unless the context is done.
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// A reporter prints the files embedded (if verbose)
+// and the duration of the phases of generations (if phases).
+type reporter struct {
+	log      logger
+	verbose  bool
+	phases   bool
+	files    int
+	size     int
+	read     time.Duration // total time reading files
+	start    time.Time     // start of the current phase
+	progress func(Progress)
+}
+
+// newReporter returns a reporter logging to l, chained to the progress callback.
+func newReporter(l logger, verbose, phases bool, progress func(Progress)) *reporter {
+	return &reporter{log: l, verbose: verbose, phases: phases, progress: progress, start: time.Now()}
+}
+
+// add reports a file added to the assets.
+func (r *reporter) add(p Progress) {
+	if r.progress != nil {
+		r.progress(p)
+	}
+	r.files++
+	r.size += p.Size
+	r.read += p.Read
+	if r.verbose {
+		r.log.log(Event{
+			Level:   "info",
+			Kind:    "file",
+			Message: fmt.Sprintf("%s: %d bytes (total %d files, %d bytes)", p.Key, p.Size, r.files, r.size),
+			Key:     p.Key,
+			Size:    p.Size,
+			Files:   r.files,
+		})
+	}
+}
+
+// collected reports the end of the collection of the files,
+// split into the walk and read phases.
+func (r *reporter) collected() {
+	if r.phases {
+		walk := time.Since(r.start) - r.read
+		r.log.log(phaseEvent("walk", walk))
+		read := phaseEvent("read", r.read)
+		read.Message += fmt.Sprintf(" (%d files, %d bytes)", r.files, r.size)
+		read.Files, read.Size = r.files, r.size
+		r.log.log(read)
+	}
+	r.start = time.Now()
+}
+
+// done reports the end of a phase.
+func (r *reporter) done(phase string) {
+	if r.phases {
+		r.log.log(phaseEvent(phase, time.Since(r.start)))
+	}
+	r.start = time.Now()
+}
+
+// reset starts a new generation.
+func (r *reporter) reset() {
+	r.files, r.size, r.read, r.start = 0, 0, 0, time.Now()
+}
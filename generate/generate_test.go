@@ -0,0 +1,142 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerate tests generations run with a context and a progress callback.
+func TestGenerate(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "gen.go")
+	var keys []string
+	err := Generate(context.Background(), []string{"-o", out, "-r", testdata, filepath.Join(testdata, "play")},
+		func(p Progress) {
+			keys = append(keys, p.Key)
+			if p.Files != len(keys) {
+				t.Errorf("expected %d files, got %d", len(keys), p.Files)
+			}
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 4 {
+		t.Errorf("expected progress for 4 files, got %v", keys)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Error(err)
+	}
+
+	// cancel after the first file
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out = filepath.Join(t.TempDir(), "gen.go")
+	err = Generate(ctx, []string{"-o", out, "-r", testdata, filepath.Join(testdata, "play")}, func(Progress) { cancel() })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation, got %v", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("expected no output, got %v", err)
+	}
+}
+
+// TestConcurrentGenerate tests generations run concurrently with different configurations.
+func TestConcurrentGenerate(t *testing.T) {
+	dir := t.TempDir()
+	inputs := []string{filepath.Join(testdata, "play", "bytes"), filepath.Join(testdata, "play")}
+	errs := make(chan error, len(inputs))
+	for i, input := range inputs {
+		go func() {
+			out := filepath.Join(dir, fmt.Sprintf("gen%d.go", i))
+			errs <- Generate(context.Background(), []string{"-o", out, "-p", fmt.Sprintf("pkg%d", i), "-m", fmt.Sprintf("files%d", i), "-r", testdata, input}, nil)
+		}()
+	}
+	for range inputs {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, want := range []int{3, 4} {
+		b, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("gen%d.go", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := string(b)
+		if !strings.Contains(s, fmt.Sprintf("package pkg%d\n", i)) || !strings.Contains(s, fmt.Sprintf("var files%d = ", i)) {
+			t.Errorf("gen%d.go: unexpected configuration:\n%s", i, s)
+		}
+		if got := strings.Count(s, `"play/`); got != want {
+			t.Errorf("gen%d.go: expected %d files, got %d", i, want, got)
+		}
+	}
+}
+
+// TestReporter tests the reports of verbose generations.
+func TestReporter(t *testing.T) {
+	var buf bytes.Buffer
+	var keys []string
+	r := newReporter(logger{w: &buf}, true, true, func(p Progress) { keys = append(keys, p.Key) })
+	r.add(Progress{Key: "a", Size: 3})
+	r.add(Progress{Key: "b", Size: 4})
+	r.collected()
+	r.done("encode")
+	r.done("write")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		"bindata: a: 3 bytes (total 1 files, 3 bytes)",
+		"bindata: b: 4 bytes (total 2 files, 7 bytes)",
+		"bindata: walk: ",
+		"bindata: read: ",
+		"bindata: encode: ",
+		"bindata: write: ",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("unexpected report:\n%s", buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, want[i]) {
+			t.Errorf("expected %q, got %q", want[i], line)
+		}
+	}
+	if len(keys) != 2 {
+		t.Errorf("progress callback not chained: %v", keys)
+	}
+
+	buf.Reset()
+	r = newReporter(logger{w: &buf}, false, false, nil)
+	r.add(Progress{Key: "a", Size: 3})
+	r.collected()
+	r.done("write")
+	if buf.Len() != 0 {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+}
+
+// TestFlagErrors tests flag errors returned by generations, and paths
+// starting with a dash given after a -- terminator.
+func TestFlagErrors(t *testing.T) {
+	err := Generate(context.Background(), []string{"-unknown", "-o", filepath.Join(t.TempDir(), "gen.go"), testdata}, nil)
+	if err == nil || !strings.Contains(err.Error(), "-unknown") {
+		t.Errorf("expected unknown flag error, got %v", err)
+	}
+
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("-dash.txt", []byte("dash"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(context.Background(), []string{"-o", "gen.go", "--", "-dash.txt"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile("gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`"-dash.txt"`)) {
+		t.Errorf("-dash.txt not embedded:\n%s", b)
+	}
+}
@@ -0,0 +1,52 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+)
+
+// GoPackageFiles returns the Go source files of the packages matching the
+// given patterns (e.g. "./..."), as listed by the go command, excluding the
+// test files. The paths are relative to the current directory if the files
+// are within it, so that the keys are those of files given on the command line.
+func GoPackageFiles(patterns []string) ([]string, error) {
+	cmd := exec.Command("go", append([]string{"list", "-json"}, patterns...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var pkg struct {
+			Dir               string
+			GoFiles, CgoFiles []string
+		}
+		if err := dec.Decode(&pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("go list: %v", err)
+		}
+		for _, name := range slices.Concat(pkg.GoFiles, pkg.CgoFiles) {
+			path := filepath.Join(pkg.Dir, name)
+			if rel, err := filepath.Rel(wd, path); err == nil && filepath.IsLocal(rel) {
+				path = rel
+			}
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
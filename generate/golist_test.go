@@ -0,0 +1,34 @@
+package generate
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGoPackageFiles tests the embedding of the Go files of packages.
+func TestGoPackageFiles(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	files, err := GoPackageFiles([]string{"./testdata/play"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(files, " "), filepath.Join("testdata", "play", "hello.go"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if _, err := GoPackageFiles([]string{"./missing"}); err == nil {
+		t.Error("expected error for missing package")
+	}
+
+	gen := newGenerator(context.Background(), nil)
+	if err := gen.run([]string{"-o", filepath.Join(t.TempDir(), "out.go"), "-go-pkg", "./testdata/play"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gen.assets[filepath.Join("testdata", "play", "hello.go")]; !ok || len(gen.assets) != 1 {
+		t.Errorf("unexpected assets %v", gen.assets)
+	}
+}
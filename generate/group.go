@@ -0,0 +1,188 @@
+package generate
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// groupTmplText is the template of the generated Go source file of a group,
+// parsed by each generator.
+const groupTmplText = `{{.Header}}{{with .Generated}}{{.}}
+
+{{end}}{{if .Tag}}{{if lt .Go 17}}// +build {{.Tag}}{{else}}//go:build {{.Tag}}{{end}}
+
+{{end}}package {{.Pkg}}
+
+// init adds the files {{.Desc}} to {{.Var}}.
+func init() {{"{"}}{{range $name, $data := .Files}}
+	{{$.Var}}[{{printf "%#v" $name}}] = {{printf "%#v" $data}}{{end}}{{range $name, $codec := .Codecs}}
+	{{$.Map}}Codecs[{{printf "%#v" $name}}] = {{printf "%#v" $codec}}{{end}}{{range $name, $mode := .Modes}}
+	{{$.Map}}Modes[{{printf "%#v" $name}}] = {{printf "%#o" $mode}}{{end}}{{range $name, $t := .ModTimes}}
+	{{$.Map}}ModTimes[{{printf "%#v" $name}}] = {{$t}}{{end}}
+}
+{{.Footer}}`
+
+// A group contains the variables required by the template of a group,
+// a set of files written to a separate file.
+type group struct {
+	File      string // output file
+	Tag       string // build constraint of the group, if any
+	Desc      string // description of the files of the group
+	Header    string // text inserted at the top of the file
+	Generated string // comment marking the generated file
+	Footer    string // text appended to the file
+	Pkg       string
+	Map       string
+	Var       string
+	Go        int // minor version of Go targeted, "// +build" lines before 1.17
+	Files     map[string]fmt.Formatter
+	Codecs    map[string]string      // codecs of the compressed files indexed by key
+	Modes     map[string]os.FileMode // permissions of the files indexed by key, if recorded
+	ModTimes  map[string]int64       // modification times of the files indexed by key, if recorded
+}
+
+// AssignGroups returns the group of each asset matching the patterns of a group.
+// Patterns are matched against the keys, or their base names if they contain no
+// slash, and "**" matches any number of directories. It is an error for an asset
+// to belong to several groups.
+func AssignGroups(assets map[string]*Asset, groups map[string][]string) (map[string]string, error) {
+	tags := make([]string, 0, len(groups))
+	for tag := range groups {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	assigned := make(map[string]string)
+	for _, tag := range tags {
+		for _, pattern := range groups[tag] {
+			if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+				return nil, fmt.Errorf("group %s: invalid pattern %q", tag, pattern)
+			}
+			for key := range assets {
+				if !matchPattern(pattern, filepath.ToSlash(key)) {
+					continue
+				}
+				if other, ok := assigned[key]; ok && other != tag {
+					return nil, fmt.Errorf("file %q belongs to groups %s and %s", key, other, tag)
+				}
+				assigned[key] = tag
+			}
+		}
+	}
+	return assigned, nil
+}
+
+// matchPattern matches a slash separated name against a glob pattern
+// where "**" matches any number of directories. Patterns without a slash
+// are matched against the base name.
+func matchPattern(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(name)
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// Patterns is a list of patterns usable as a repeatable command line flag.
+type Patterns []string
+
+// String returns the patterns as they would appear on the command line.
+func (ps *Patterns) String() string {
+	return strings.Join(*ps, ", ")
+}
+
+// Set validates a pattern and adds it to the list.
+func (ps *Patterns) Set(value string) error {
+	if _, err := path.Match(strings.ReplaceAll(value, "**", "*"), ""); err != nil {
+		return fmt.Errorf("invalid pattern %q", value)
+	}
+	*ps = append(*ps, value)
+	return nil
+}
+
+// Match reports whether the slash separated name matches one of the patterns.
+// An empty list matches all names.
+func (ps Patterns) Match(name string) bool {
+	if len(ps) == 0 {
+		return true
+	}
+	for _, p := range ps {
+		if matchPattern(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupFile returns the name of the output file of a group,
+// e.g. "assets_premium.go" for group "premium" and output "assets.go".
+func groupFile(out, tag string) string {
+	suffix := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, tag)
+	return strings.TrimSuffix(out, ".go") + "_" + suffix + ".go"
+}
+
+// A Route sends the files matching a pattern to another output file.
+type Route struct {
+	Pattern string
+	File    string
+}
+
+// Routes is a list of routes usable as a repeatable command line flag.
+type Routes []Route
+
+// String returns the routes as they would appear on the command line.
+func (rs *Routes) String() string {
+	var s []string
+	for _, r := range *rs {
+		s = append(s, r.Pattern+"="+r.File)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Set parses a route of the form "pattern=file" and adds it to the list.
+func (rs *Routes) Set(value string) error {
+	i := strings.LastIndex(value, "=")
+	if i <= 0 || i == len(value)-1 {
+		return fmt.Errorf("invalid route %q, expected pattern=file", value)
+	}
+	r := Route{value[:i], value[i+1:]}
+	if _, err := path.Match(strings.ReplaceAll(r.Pattern, "**", "*"), ""); err != nil {
+		return fmt.Errorf("invalid pattern %q", r.Pattern)
+	}
+	*rs = append(*rs, r)
+	return nil
+}
+
+// Assign returns the first route matching each asset, if any.
+func (rs Routes) Assign(assets map[string]*Asset) map[string]Route {
+	routed := make(map[string]Route)
+	for key := range assets {
+		for _, r := range rs {
+			if matchPattern(r.Pattern, filepath.ToSlash(key)) {
+				routed[key] = r
+				break
+			}
+		}
+	}
+	return routed
+}
+
+// Patterns returns the patterns of the routes to file.
+func (rs Routes) Patterns(file string) string {
+	var patterns []string
+	for _, r := range rs {
+		if r.File == file {
+			patterns = append(patterns, r.Pattern)
+		}
+	}
+	return strings.Join(patterns, ", ")
+}
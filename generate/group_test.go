@@ -0,0 +1,169 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestAssignGroups tests the assignment of files to groups.
+func TestAssignGroups(t *testing.T) {
+	assets := map[string]*Asset{
+		"a.txt":           {},
+		"premium/b.txt":   {},
+		"premium/x/c.txt": {},
+		"demo/d.sample":   {},
+	}
+	got, err := AssignGroups(assets, map[string][]string{
+		"premium": {"premium/**"},
+		"demo":    {"*.sample"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"premium/b.txt":   "premium",
+		"premium/x/c.txt": "premium",
+		"demo/d.sample":   "demo",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if _, err := AssignGroups(assets, map[string][]string{"a": {"*.txt"}, "b": {"premium/*"}}); err == nil {
+		t.Error("expected error for file in several groups")
+	}
+	if got := groupFile("dir/assets.go", "demo-content"); got != "dir/assets_demo_content.go" {
+		t.Errorf("unexpected group file %s", got)
+	}
+}
+
+// TestGroups tests the generation of a file per group.
+func TestGroups(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "bindata.json")
+	if err := os.WriteFile(config, []byte(`{"groups": {"premium": ["11"]}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "gen.go")
+	runTest(t, "", "-c", config, "-o", out, "-r", filepath.Join(testdata, "play", "bytes"),
+		filepath.Join(testdata, "play", "bytes", "11"))
+
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+//go:build premium
+
+package main
+
+// init adds the files of group premium to bindata.
+func init() {
+	bindata["11"] = []byte{
+		0x31, 0x30, 0x2b, 0x31, 0x20, 0x62, 0x79, 0x74, 0x65, 0x73, 0x21,
+	}
+}
+`
+	b, err := os.ReadFile(filepath.Join(dir, "gen_premium.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ref {
+		t.Errorf("unexpected output:\n%s", b)
+	}
+}
+
+// TestRoutes tests the routing of files to other output files.
+func TestRoutes(t *testing.T) {
+	var rs Routes
+	for _, v := range []string{"templates/**=templates_gen.go", "*.css=static_gen.go", "static/**=static_gen.go"} {
+		if err := rs.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range []string{"a.go", "=a.go", "*=", "[=a.go"} {
+		if err := new(Routes).Set(v); err == nil {
+			t.Errorf("expected error for route %q", v)
+		}
+	}
+	got := rs.Assign(map[string]*Asset{
+		"a.txt":             {},
+		"templates/x/a.tpl": {},
+		"static/a.css":      {},
+		"static/a.js":       {},
+	})
+	want := map[string]Route{
+		"templates/x/a.tpl": rs[0],
+		"static/a.css":      rs[1],
+		"static/a.js":       rs[2],
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := rs.Patterns("static_gen.go"); got != "*.css, static/**" {
+		t.Errorf("unexpected patterns %q", got)
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "gen.go")
+	routed := filepath.Join(dir, "gen_11.go")
+	runTest(t, "", "-o", out, "-o-for", "11="+routed, "-r", filepath.Join(testdata, "play", "bytes"),
+		filepath.Join(testdata, "play", "bytes", "11"))
+
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// init adds the files matching 11 to bindata.
+func init() {
+	bindata["11"] = []byte{
+		0x31, 0x30, 0x2b, 0x31, 0x20, 0x62, 0x79, 0x74, 0x65, 0x73, 0x21,
+	}
+}
+`
+	b, err := os.ReadFile(routed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ref {
+		t.Errorf("unexpected output:\n%s", b)
+	}
+}
+
+// TestOnly tests the update of some files of an existing output file.
+func TestOnly(t *testing.T) {
+	// whatever the layout, the files embedded with go:embed are kept as well
+	for _, args := range [][]string{nil, {"-embed-over", "2"}, {"-layout", "slice"}, {"-layout", "blob"}, {"-layout", "vars"}, {"-target", "tinygo"}} {
+		in, out := t.TempDir(), filepath.Join(t.TempDir(), "gen.go")
+		write := func(files map[string]string) {
+			for name, data := range files {
+				path := filepath.Join(in, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(data), 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		write(map[string]string{"a/x.txt": "x1", "a/old.txt": "old", "b/y.txt": "y1", "b/z.txt": "z"})
+		if err := runGenerate(append(args, "-o", out, "-r", in, in)); err != nil {
+			t.Fatal(err)
+		}
+		write(map[string]string{"a/x.txt": "x2", "a/new.txt": "new", "b/y.txt": "y2"})
+		if err := os.Remove(filepath.Join(in, "a", "old.txt")); err != nil {
+			t.Fatal(err)
+		}
+		if err := runGenerate(append(args, "-only", "a/**", "-o", out, "-r", in, in)); err != nil {
+			t.Fatal(err)
+		}
+
+		g, err := ParseGenerated(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string][]byte{"a/x.txt": []byte("x2"), "a/new.txt": []byte("new"), "b/y.txt": []byte("y1"), "b/z.txt": []byte("z")}
+		if !reflect.DeepEqual(g.Data, want) {
+			t.Errorf("%v: unexpected files %q", args, g.Data)
+		}
+	}
+}
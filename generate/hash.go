@@ -0,0 +1,124 @@
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"maps"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SHA256 returns the SHA-256 checksum of the contents of the asset, computed
+// once for all its uses (lock, comments, -stat, ...).
+func (a *Asset) SHA256() [sha256.Size]byte {
+	if a.sum == nil {
+		sum := sha256.Sum256(a.Data)
+		a.sum = &sum
+	}
+	return *a.sum
+}
+
+// XXHash returns the XXH64 hash of the contents of the asset, computed once.
+func (a *Asset) XXHash() uint64 {
+	if a.xxhash == nil {
+		h := XXHash64(a.Data)
+		a.xxhash = &h
+	}
+	return *a.xxhash
+}
+
+// cacheHashes are the hashes of the contents of the assets which can be
+// selected with -cache-hash for the content-addressed names and the bundle
+// version, which are cache keys: XXH64 is much faster than SHA-256 but not
+// collision resistant, so the checksums verifying integrity stay SHA-256.
+var cacheHashes = map[string]func(a *Asset) []byte{
+	"sha256": func(a *Asset) []byte { sum := a.SHA256(); return sum[:] },
+	"xxhash": func(a *Asset) []byte { return binary.BigEndian.AppendUint64(nil, a.XXHash()) },
+}
+
+// hashAssets computes the hashes of the contents of the assets held in
+// memory concurrently, with one worker per CPU, by calling hash on each of
+// them, e.g. (*Asset).SHA256, so that they are computed once and for all
+// before being used.
+func hashAssets(assets map[string]*Asset, hash func(a *Asset)) {
+	work := make(chan *Asset)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(assets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range work {
+				hash(a)
+			}
+		}()
+	}
+	for _, a := range assets {
+		if !a.Lazy {
+			work <- a
+		}
+	}
+	close(work)
+	wg.Wait()
+}
+
+// hashLen is the number of hexadecimal digits of the hash in content-addressed names.
+const hashLen = 8
+
+// hashedName returns the content-addressed name of a file, obtained by
+// inserting its hash before the extension of the base name,
+// e.g. "js/app.js" becomes "js/app.3f9ab2c1.js".
+func hashedName(name string, sum []byte) string {
+	hash := hex.EncodeToString(sum)[:hashLen]
+	ext := path.Ext(name)
+	if ext == path.Base(name) { // dot files such as ".htaccess"
+		ext = ""
+	}
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// obfuscatedLen is the number of bytes of the hash in obfuscated keys.
+const obfuscatedLen = 16
+
+// ObfuscatedKey returns the key hiding the name of a file: a hash of the
+// name salted with salt, as computed at run time by the generated code.
+func ObfuscatedKey(salt, name string) string {
+	sum := sha256.Sum256([]byte(salt + name))
+	return hex.EncodeToString(sum[:obfuscatedLen])
+}
+
+// versionLen is the number of hexadecimal digits of bundle versions.
+const versionLen = 16
+
+// bundleVersion returns a fingerprint of the keys and contents of the assets,
+// which changes whenever a file is added, removed, renamed or modified,
+// with the hashes of their contents returned by hash.
+func bundleVersion(assets map[string]*Asset, hash func(a *Asset) []byte) string {
+	h := sha256.New()
+	for _, key := range slices.Sorted(maps.Keys(assets)) {
+		h.Write([]byte(filepath.ToSlash(key)))
+		h.Write([]byte{0})
+		h.Write(hash(assets[key]))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:versionLen]
+}
+
+// longestFirst returns the keys of m sorted by decreasing length, then alphabetically.
+func longestFirst(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,132 @@
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestHashedName tests the insertion of content hashes in file names.
+func TestHashedName(t *testing.T) {
+	tests := map[string]string{
+		"app.js":          "app.2cf24dba.js",
+		"css/app.min.css": "css/app.min.2cf24dba.css",
+		"dir.d/LICENSE":   "dir.d/LICENSE.2cf24dba",
+		".htaccess":       ".htaccess.2cf24dba",
+	}
+	for in, out := range tests {
+		if got := hashedName(in, cacheHashes["sha256"](&Asset{Data: []byte("hello")})); got != out {
+			t.Errorf("%s: expected %s, got %s", in, out, got)
+		}
+	}
+}
+
+// TestLongestFirst tests the ordering of rewritten names.
+func TestLongestFirst(t *testing.T) {
+	got := longestFirst(map[string]string{"a.js": "", "a.json": "", "b.js": "", "c": ""})
+	if want := []string{"a.json", "a.js", "b.js", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestBundleVersion tests the fingerprint of the assets.
+func TestBundleVersion(t *testing.T) {
+	assets := map[string]*Asset{"a": {Data: []byte("a")}, "b": {Data: []byte("b")}}
+	hash := cacheHashes["sha256"]
+	v := bundleVersion(assets, hash)
+	if len(v) != versionLen {
+		t.Errorf("unexpected version %q", v)
+	}
+	if got := bundleVersion(map[string]*Asset{"b": {Data: []byte("b")}, "a": {Data: []byte("a")}}, hash); got != v {
+		t.Errorf("version depends on order: %s and %s", v, got)
+	}
+	for _, other := range []map[string]*Asset{
+		{"a": {Data: []byte("a")}},
+		{"a": {Data: []byte("a")}, "c": {Data: []byte("b")}},
+		{"a": {Data: []byte("a")}, "b": {Data: []byte("c")}},
+	} {
+		if bundleVersion(other, hash) == v {
+			t.Errorf("same version for %v", other)
+		}
+	}
+}
+
+// TestCacheHash tests the concurrent hashing of the assets and the choice of
+// the hash of content-addressed names.
+func TestCacheHash(t *testing.T) {
+	assets := make(map[string]*Asset)
+	for i := range 100 {
+		assets[fmt.Sprint(i)] = &Asset{Data: []byte(fmt.Sprint(i))}
+	}
+	assets["lazy"] = &Asset{Lazy: true}
+	hashAssets(assets, func(a *Asset) { a.SHA256() })
+	for key, a := range assets {
+		if a.Lazy != (a.sum == nil) || !a.Lazy && *a.sum != sha256.Sum256(a.Data) {
+			t.Errorf("%s: unexpected checksum %x", key, a.sum)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(testdata, "play", "bytes", "11"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	for hash, sum := range map[string][]byte{
+		"sha256": sum[:],
+		"xxhash": binary.BigEndian.AppendUint64(nil, XXHash64(data)),
+	} {
+		out := filepath.Join(t.TempDir(), "bindata.go")
+		if err := runGenerate([]string{"-o", out, "-hash-names", "-cache-hash", hash, "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(out)
+		if want := hashedName("play/bytes/11", sum); err != nil || !strings.Contains(string(b), want) {
+			t.Errorf("%s: %s not found in the generated file (%v)", hash, want, err)
+		}
+	}
+	if err := runGenerate([]string{"-cache-hash", "md5", testdata}); err == nil {
+		t.Error("expected error with -cache-hash md5")
+	}
+}
+
+// TestObfuscatedKeys tests that the names of the files are hidden in the generated code.
+func TestObfuscatedKeys(t *testing.T) {
+	if a, b := ObfuscatedKey("s", "a.txt"), ObfuscatedKey("t", "a.txt"); a == b || len(a) != 2*obfuscatedLen {
+		t.Errorf("unexpected keys %q and %q", a, b)
+	}
+
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	data, ok := bindataGet("play/bytes/11")
+	fmt.Printf("%s %v\n", data, ok)
+	_, ok = bindataGet("play/bytes/14")
+	fmt.Println(ok)
+}
+`
+	const want = "10+1 bytes! true\nfalse\n"
+	for _, args := range [][]string{nil, {"-layout", "slice"}, {"-layout", "blob"}} {
+		args = append(args, "-obfuscate-keys", "pepper", "-r", testdata, filepath.Join(testdata, "play", "bytes"))
+		if out := runGenerated(t, main, args...); out != want {
+			t.Errorf("%v: unexpected output:\n%s", args, out)
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "bindata.go")
+	if err := runGenerate([]string{"-o", out, "-obfuscate-keys", "pepper", "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(out); err != nil || strings.Contains(string(b), "play/bytes") {
+		t.Errorf("file names leaked into the generated file (%v)", err)
+	}
+	if err := runGenerate([]string{"-obfuscate-keys", "pepper", "-fs", testdata}); err == nil {
+		t.Error("expected error with -fs")
+	}
+}
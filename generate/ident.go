@@ -0,0 +1,72 @@
+package generate
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// initialisms are the words kept in upper case in identifiers.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "CSV": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JS": true, "JSON": true, "PDF": true, "RPC": true,
+	"SQL": true, "SSH": true, "SVG": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "XML": true, "XSRF": true, "XSS": true, "YAML": true,
+}
+
+// identifier converts a file path to a CamelCase Go identifier,
+// e.g. "static/index.html" becomes "StaticIndexHTML".
+// The result may be empty or start with a digit, so callers usually add a prefix.
+func identifier(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var id strings.Builder
+	for _, w := range words {
+		if u := strings.ToUpper(w); initialisms[u] {
+			id.WriteString(u)
+			continue
+		}
+		r := []rune(w)
+		id.WriteString(strings.ToUpper(string(r[0])))
+		id.WriteString(string(r[1:]))
+	}
+	return id.String()
+}
+
+// A constant is a named constant holding the key of a file.
+type constant struct {
+	Name  string
+	Value string
+}
+
+// constants returns the constants naming the given sorted keys.
+// It fails if two keys map to the same identifier.
+func constants(prefix string, keys []string) ([]constant, error) {
+	consts := make([]constant, 0, len(keys))
+	seen := make(map[string]string)
+	for _, key := range keys {
+		name := prefix + identifier(key)
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("cannot derive a constant name for %q: %q is not a valid identifier", key, name)
+		}
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("files %q and %q both map to constant %s", other, key, name)
+		}
+		seen[name] = key
+		consts = append(consts, constant{name, key})
+	}
+	return consts, nil
+}
+
+// unexported returns name with its first letter in lower case.
+func unexported(name string) string {
+	r := []rune(name)
+	if len(r) == 0 {
+		return name
+	}
+	return strings.ToLower(string(r[0])) + string(r[1:])
+}
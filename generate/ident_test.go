@@ -0,0 +1,30 @@
+package generate
+
+import "testing"
+
+// TestIdentifier tests the conversion of file paths to identifiers.
+func TestIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"index.html":           "IndexHTML",
+		"static/js/app.min.js": "StaticJSAppMinJS",
+		"img/gopher-2x.png":    "ImgGopher2xPng",
+		"données/été.json":     "DonnéesÉtéJSON",
+		"_hidden/.keep":        "HiddenKeep",
+		"404.html":             "404HTML",
+	}
+	for in, out := range tests {
+		if got := identifier(in); got != out {
+			t.Errorf("%s: expected %s, got %s", in, out, got)
+		}
+	}
+}
+
+// TestConstantsCollision tests that colliding constant names are reported.
+func TestConstantsCollision(t *testing.T) {
+	if _, err := constants("Asset", []string{"a-b.txt", "a_b.txt"}); err == nil {
+		t.Error("expected collision error")
+	}
+	if _, err := constants("", []string{"404.html"}); err == nil {
+		t.Error("expected invalid identifier error")
+	}
+}
@@ -0,0 +1,115 @@
+package generate
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// An ignoreRule is a pattern of an ignore file, with .gitignore semantics.
+type ignoreRule struct {
+	dir      string   // directory of the ignore file
+	segments []string // slash separated segments of the pattern
+	negate   bool     // pattern starts with '!'
+	dirOnly  bool     // pattern ends with '/'
+}
+
+// An IgnoreList is a list of ignore rules; the last matching rule wins.
+type IgnoreList []ignoreRule
+
+// ParseIgnore parses the contents of an ignore file located in dir.
+func ParseIgnore(dir, contents string) IgnoreList {
+	var l IgnoreList
+	s := bufio.NewScanner(strings.NewReader(contents))
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		r := ignoreRule{dir: dir}
+		if line[0] == '!' {
+			r.negate, line = true, line[1:]
+		} else if line[0] == '\\' {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly, line = true, strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		// patterns without an inner slash match at any depth
+		if !strings.Contains(strings.TrimPrefix(line, "/"), "/") && line[0] != '/' {
+			line = "**/" + line
+		}
+		r.segments = strings.Split(strings.TrimPrefix(line, "/"), "/")
+		l = append(l, r)
+	}
+	return l
+}
+
+// LoadIgnore reads the ignore files of dir with the given names and appends
+// their rules to l. It returns a new list, l is not modified.
+func (l IgnoreList) LoadIgnore(dir string, names []string) (IgnoreList, error) {
+	l = l[:len(l):len(l)]
+	for _, name := range names {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, ParseIgnore(dir, string(b))...)
+	}
+	return l, nil
+}
+
+// Ignored reports whether the file or directory at the given path is ignored.
+func (l IgnoreList) Ignored(name string, isDir bool) bool {
+	ignored := false
+	for _, r := range l {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(r.dir, name)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if matchSegments(r.segments, strings.Split(filepath.ToSlash(rel), "/")) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches the segments of a path against the segments of
+// a glob pattern, where a "**" segment matches zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// isIgnoreFile reports whether name is the name of a bindata specific ignore file,
+// which is never embedded.
+func isIgnoreFile(name string) bool {
+	return name == ".bindataignore"
+}
@@ -0,0 +1,81 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestIgnored tests the matching of paths against ignore rules.
+func TestIgnored(t *testing.T) {
+	root := filepath.FromSlash("/root")
+	l := ParseIgnore(root, `
+# comment
+*.log
+!keep.log
+/build/
+docs/**/*.tmp
+\#hash
+`)
+	l = append(l, ParseIgnore(filepath.Join(root, "sub"), "local")...)
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"a.log", false, true},
+		{"x/y/a.log", false, true},
+		{"x/keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"x/build", true, false},
+		{"docs/a.tmp", false, true},
+		{"docs/a/b/c.tmp", false, true},
+		{"other/a.tmp", false, false},
+		{"#hash", false, true},
+		{"local", false, false},
+		{"sub/local", false, true},
+		{"sub/deep/local", true, true},
+	}
+	for _, test := range tests {
+		path := filepath.Join(root, filepath.FromSlash(test.path))
+		if got := l.Ignored(path, test.isDir); got != test.ignored {
+			t.Errorf("%s (dir: %v): expected %v, got %v", test.path, test.isDir, test.ignored, got)
+		}
+	}
+}
+
+// TestIgnoreFiles tests that ignore files are honoured during traversal.
+func TestIgnoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		".bindataignore": "*.bak\n",
+		".gitignore":     "tmp/\n",
+		"a.txt":          "a",
+		"a.bak":          "b",
+		"tmp/c.txt":      "c",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const ref = `// Code generated by bindata. DO NOT EDIT.
+
+package main
+
+// bindata stores binary files as strings indexed by file paths.
+var bindata = map[string]string{
+	".gitignore": "" +
+		"\x74\x6d\x70\x2f\x0a",
+	"a.txt": "" +
+		"\x61",
+}
+`
+	runTest(t, ref, "-s", "-gitignore", "-r", dir, dir)
+}
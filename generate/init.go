@@ -0,0 +1,162 @@
+package generate
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// defaultGenerated is the default comment marking the files generated by bindata.
+const defaultGenerated = "// Code generated by bindata. DO NOT EDIT."
+
+// generatedComments match the comments marking generated files:
+// the Go convention and the comment of previous versions of bindata.
+var generatedComments = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$|^// This file is generated\. Do not edit directly\.$`)
+
+// assetDirs are the conventional names of asset directories proposed by init.
+var assetDirs = []string{"assets", "static", "public", "templates", "web", "dist"}
+
+// runInit executes the init subcommand, which adds a go:generate directive
+// running bindata to a source file of the package in the current directory.
+func runInit(args []string) error {
+	var out, file string
+	var dryRun bool
+	fs := flag.NewFlagSet("bindata init", flag.ContinueOnError)
+	fs.StringVar(&out, "o", "", "output file (default: bindata.go or bindata_gen.go)")
+	fs.StringVar(&file, "file", "", "source file receiving the directive (default: chosen from the package)")
+	fs.BoolVar(&dryRun, "n", false, "print the directive without adding it")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	sources, err := packageSources(".")
+	if err != nil {
+		return err
+	}
+	if len(sources) == 0 && file == "" {
+		return fmt.Errorf("no Go source files in the current directory")
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		for _, dir := range assetDirs {
+			if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+				paths = append(paths, dir)
+			}
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("no asset directory found, specify the files to embed")
+		}
+	}
+
+	if out == "" {
+		out = "bindata.go"
+		if b, err := os.ReadFile(out); err == nil && !generatedComments.Match(b) {
+			out = "bindata_gen.go"
+		}
+	}
+	if file == "" {
+		file = chooseSource(sources)
+	}
+
+	directive := "//go:generate bindata -o " + out + " "
+	if slices.ContainsFunc(paths, func(path string) bool { return strings.HasPrefix(path, "-") }) {
+		directive += "-- " // paths not to be taken for flags
+	}
+	directive += strings.Join(paths, " ")
+	if dryRun {
+		fmt.Printf("%s: %s\n", file, directive)
+		return nil
+	}
+	if err := insertDirective(file, directive); err != nil {
+		return err
+	}
+	fmt.Printf("added to %s: %s\n", file, directive)
+	return nil
+}
+
+// packageSources returns the names of the source files of the package in dir,
+// excluding test files and generated files.
+func packageSources(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var sources []string
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !generatedComments.Match(b) {
+			sources = append(sources, file)
+		}
+	}
+	sort.Strings(sources)
+	return sources, nil
+}
+
+// chooseSource picks the source file of a package that should hold the directive:
+// the file named after the package or the directory, main.go or doc.go if any,
+// or else the first file in alphabetical order.
+func chooseSource(sources []string) string {
+	candidates := []string{"main.go", "doc.go"}
+	if pkg, err := PackageName("."); err == nil {
+		candidates = append([]string{pkg + ".go"}, candidates...)
+	}
+	if abs, err := filepath.Abs("."); err == nil {
+		candidates = append([]string{filepath.Base(abs) + ".go"}, candidates...)
+	}
+	for _, c := range candidates {
+		for _, s := range sources {
+			if filepath.Base(s) == c {
+				return s
+			}
+		}
+	}
+	return sources[0]
+}
+
+// insertDirective inserts a go:generate directive after the package clause of file.
+// It fails if the file already contains a directive running bindata.
+func insertDirective(file, directive string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(b, []byte("//go:generate bindata")) {
+		return fmt.Errorf("%s already contains a go:generate directive running bindata", file)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, b, parser.PackageClauseOnly)
+	if err != nil {
+		return err
+	}
+	// insert at the end of the line of the package clause
+	offset := fset.Position(f.Name.End()).Offset
+	if i := bytes.IndexByte(b[offset:], '\n'); i >= 0 {
+		offset += i + 1
+	} else {
+		b, offset = append(b, '\n'), len(b)+1
+	}
+	var buf bytes.Buffer
+	buf.Write(b[:offset])
+	buf.WriteString("\n" + directive + "\n")
+	buf.Write(b[offset:])
+	return WriteFile(file, func(w io.Writer) error {
+		_, err := buf.WriteTo(w)
+		return err
+	})
+}
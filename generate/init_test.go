@@ -0,0 +1,57 @@
+package generate
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestInit tests the insertion of the go:generate directive.
+func TestInit(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for name, contents := range map[string]string{
+		"a.go":        "package web\n",
+		"web.go":      "// Package web serves the site.\npackage web\n\nfunc Serve() {}\n",
+		"web_test.go": "package web\n",
+		"bindata.go":  "package web\n\n// handwritten\n",
+	} {
+		if err := os.WriteFile(name, []byte(contents), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir("static", 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runInit(nil); err != nil {
+		t.Fatal(err)
+	}
+	const ref = `// Package web serves the site.
+package web
+
+//go:generate bindata -o bindata_gen.go static
+
+func Serve() {}
+`
+	b, err := os.ReadFile("web.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ref {
+		t.Errorf("unexpected source:\n%s", b)
+	}
+
+	if err := runInit(nil); err == nil {
+		t.Error("expected error for existing directive")
+	}
+	// paths starting with a dash follow a -- terminator
+	if err := os.WriteFile("web.go", []byte("package web\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runInit([]string{"-file", "web.go", "-o", "gen.go", "--", "-static"}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile("web.go"); err != nil || !strings.Contains(string(b), "//go:generate bindata -o gen.go -- -static\n") {
+		t.Errorf("unexpected source %q (%v)", b, err)
+	}
+}
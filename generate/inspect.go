@@ -0,0 +1,408 @@
+package generate
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// A Generated is the contents of files previously generated by bindata.
+type Generated struct {
+	Var    string            // name of the map or slice variable storing the data, empty for the vars layout
+	Data   map[string][]byte // stored data indexed by key
+	Codecs map[string]string // codecs of the compressed files indexed by key
+}
+
+// Keys returns the sorted keys of the generated files.
+func (g *Generated) Keys() []string {
+	keys := make([]string, 0, len(g.Data))
+	for key := range g.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Read returns the decompressed contents of the file stored under key.
+func (g *Generated) Read(key string) ([]byte, error) {
+	data, ok := g.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("no file %q", key)
+	}
+	data, err := Decompress(g.Codecs[key], data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", key, err)
+	}
+	return data, nil
+}
+
+// ParseGenerated parses files generated by bindata, including the files of
+// groups and the files embedded with go:embed (-embed-over), read from their
+// directory next to the generated file.
+func ParseGenerated(files ...string) (*Generated, error) {
+	g := &Generated{Data: make(map[string][]byte), Codecs: make(map[string]string)}
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, f)
+	}
+
+	// the data map or slice, the codecs map, the blob and the variables of
+	// the vars layout are identified by their doc comments
+	var codecs string
+	var blob []byte
+	var spans []span
+	for _, f := range parsed {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || (gd.Tok != token.VAR && gd.Tok != token.CONST) || len(gd.Specs) != 1 {
+				continue
+			}
+			vs := gd.Specs[0].(*ast.ValueSpec)
+			if len(vs.Names) != 1 || len(vs.Values) != 1 || gd.Doc == nil {
+				continue
+			}
+			name, doc, value := vs.Names[0].Name, gd.Doc.Text(), vs.Values[0]
+			var err error
+			switch {
+			case strings.HasPrefix(doc, name+" stores binary files"):
+				g.Var = name
+				err = g.setEntries(fset, name, value, g.setData)
+			case strings.HasPrefix(doc, name+" stores the offsets and sizes of the files"):
+				g.Var = name
+				spans, err = parseSpans(fset, name, value)
+			case strings.Contains(doc, "stored compressed to their codec"):
+				codecs = name
+				err = g.setEntries(fset, name, value, g.setCodec)
+			case strings.HasPrefix(doc, name+" holds the contents of all the files, concatenated"):
+				if blob, err = evalBytes(value); err != nil {
+					err = fmt.Errorf("%s: %v", fset.Position(value.Pos()), err)
+				}
+			case strings.HasPrefix(doc, name+" holds the contents of "):
+				if err = g.setVar(strings.TrimPrefix(doc, name+" holds the contents of "), value); err != nil {
+					err = fmt.Errorf("%s: %v", fset.Position(value.Pos()), err)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, s := range spans {
+		if s.offset < 0 || s.size < 0 || s.offset+s.size > len(blob) {
+			return nil, fmt.Errorf("%s: %d bytes at offset %d out of the blob of %d bytes", s.key, s.size, s.offset, len(blob))
+		}
+		g.Data[s.key] = blob[s.offset : s.offset+s.size]
+	}
+	if g.Var == "" && len(g.Data) == 0 {
+		return nil, fmt.Errorf("no data generated by bindata in %s", strings.Join(files, ", "))
+	}
+
+	// the files of groups are assigned in init functions, and the embedded
+	// files added in a loop over the map of their names
+	for i, f := range parsed {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Name.Name != "init" || fd.Recv != nil || fd.Body == nil {
+				continue
+			}
+			for _, stmt := range fd.Body.List {
+				if rs, ok := stmt.(*ast.RangeStmt); ok {
+					if err := g.setEmbedded(fset, filepath.Dir(files[i]), rs); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				as, ok := stmt.(*ast.AssignStmt)
+				if !ok || len(as.Lhs) != 1 || len(as.Rhs) != 1 {
+					continue
+				}
+				ix, ok := as.Lhs[0].(*ast.IndexExpr)
+				if !ok {
+					continue
+				}
+				id, ok := ix.X.(*ast.Ident)
+				if !ok || (id.Name != g.Var && id.Name != codecs) {
+					continue
+				}
+				key, err := evalString(ix.Index)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", fset.Position(ix.Pos()), err)
+				}
+				dst := g.setData
+				if id.Name != g.Var {
+					dst = g.setCodec
+				}
+				if err := dst(key, as.Rhs[0]); err != nil {
+					return nil, fmt.Errorf("%s: %v", fset.Position(as.Pos()), err)
+				}
+			}
+		}
+	}
+	return g, nil
+}
+
+// setEntries sets the files of the literal e of the variable name, with the
+// pairs of keys and values of a map, or of the structs of the slice layout.
+func (g *Generated) setEntries(fset *token.FileSet, name string, e ast.Expr, dst func(key string, e ast.Expr) error) error {
+	lit, ok := e.(*ast.CompositeLit)
+	if !ok {
+		return fmt.Errorf("%s: unexpected value of %s", fset.Position(e.Pos()), name)
+	}
+	for _, elt := range lit.Elts {
+		var k, v ast.Expr
+		switch elt := elt.(type) {
+		case *ast.KeyValueExpr:
+			k, v = elt.Key, elt.Value
+		case *ast.CompositeLit:
+			if len(elt.Elts) == 2 {
+				k, v = elt.Elts[0], elt.Elts[1]
+			}
+		}
+		if k == nil {
+			return fmt.Errorf("%s: unexpected element in %s", fset.Position(elt.Pos()), name)
+		}
+		key, err := evalString(k)
+		if err != nil {
+			return fmt.Errorf("%s: %v", fset.Position(elt.Pos()), err)
+		}
+		if err := dst(key, v); err != nil {
+			return fmt.Errorf("%s: %v", fset.Position(elt.Pos()), err)
+		}
+	}
+	return nil
+}
+
+// A span is the position of a file in the blob of the blob layout.
+type span struct {
+	key          string
+	offset, size int
+}
+
+// parseSpans parses the structs of the literal e of the variable name
+// locating the files in the blob of the blob layout.
+func parseSpans(fset *token.FileSet, name string, e ast.Expr) ([]span, error) {
+	lit, ok := e.(*ast.CompositeLit)
+	if !ok {
+		return nil, fmt.Errorf("%s: unexpected value of %s", fset.Position(e.Pos()), name)
+	}
+	spans := make([]span, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		s, ok := elt.(*ast.CompositeLit)
+		if !ok || len(s.Elts) != 3 {
+			return nil, fmt.Errorf("%s: unexpected element in %s", fset.Position(elt.Pos()), name)
+		}
+		key, err := evalString(s.Elts[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", fset.Position(elt.Pos()), err)
+		}
+		var ints [2]int
+		for i, e := range s.Elts[1:] {
+			if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.INT {
+				ints[i], err = strconv.Atoi(lit.Value)
+			} else {
+				err = fmt.Errorf("unexpected offset or size %T", e)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", fset.Position(elt.Pos()), err)
+			}
+		}
+		spans = append(spans, span{key, ints[0], ints[1]})
+	}
+	return spans, nil
+}
+
+// setVar sets the data of a file of the vars layout from the doc of its
+// variable, following "holds the contents of" with the quoted key, and the
+// expression of its value.
+func (g *Generated) setVar(doc string, e ast.Expr) error {
+	quoted, err := strconv.QuotedPrefix(doc)
+	if err != nil {
+		return fmt.Errorf("no key in the doc comment: %v", err)
+	}
+	key, err := strconv.Unquote(quoted)
+	if err != nil {
+		return err
+	}
+	return g.setData(key, e)
+}
+
+// setData sets the data of a file from the expression of its value.
+func (g *Generated) setData(key string, e ast.Expr) error {
+	data, err := evalBytes(e)
+	if err != nil {
+		return err
+	}
+	g.Data[key] = data
+	return nil
+}
+
+// setEmbedded sets the data of the files embedded with go:embed from the
+// loop rs adding them to the map, reading them from the embedded directory
+// in dir. Other loops are ignored.
+func (g *Generated) setEmbedded(fset *token.FileSet, dir string, rs *ast.RangeStmt) error {
+	lit, ok := rs.X.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	// the files are read by ReadFile("<embedded directory>/" + file)
+	var prefix string
+	ast.Inspect(rs.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return prefix == ""
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "ReadFile" {
+			if arg, ok := call.Args[0].(*ast.BinaryExpr); ok {
+				prefix, _ = evalString(arg.X)
+			}
+		}
+		return prefix == ""
+	})
+	if prefix == "" {
+		return nil
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return fmt.Errorf("%s: unexpected element in the embedded files", fset.Position(elt.Pos()))
+		}
+		key, err := evalString(kv.Key)
+		if err != nil {
+			return fmt.Errorf("%s: %v", fset.Position(kv.Pos()), err)
+		}
+		file, err := evalString(kv.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %v", fset.Position(kv.Pos()), err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(prefix+file)))
+		if err != nil {
+			return err
+		}
+		g.Data[key] = data
+	}
+	return nil
+}
+
+// setCodec sets the codec of a file from the expression of its value.
+func (g *Generated) setCodec(key string, e ast.Expr) error {
+	codec, err := evalString(e)
+	if err != nil {
+		return err
+	}
+	g.Codecs[key] = codec
+	return nil
+}
+
+// evalString evaluates a constant string expression.
+func evalString(e ast.Expr) (string, error) {
+	b, err := evalBytes(e)
+	return string(b), err
+}
+
+// evalBytes evaluates the constant expressions of data generated by bindata:
+// string literals, their concatenations, byte slice literals and conversions
+// of strings to byte slices.
+func evalBytes(e ast.Expr) ([]byte, error) {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return evalBytes(e.X)
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			break
+		}
+		s, err := strconv.Unquote(e.Value)
+		return []byte(s), err
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			break
+		}
+		x, err := evalBytes(e.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := evalBytes(e.Y)
+		return append(x, y...), err
+	case *ast.CompositeLit:
+		data := make([]byte, 0, len(e.Elts))
+		for _, elt := range e.Elts {
+			lit, ok := elt.(*ast.BasicLit)
+			if !ok || (lit.Kind != token.INT && lit.Kind != token.CHAR) {
+				return nil, fmt.Errorf("unexpected byte %T", elt)
+			}
+			var b uint64
+			var err error
+			if lit.Kind == token.CHAR {
+				var r rune
+				r, _, _, err = strconv.UnquoteChar(lit.Value[1:len(lit.Value)-1], '\'')
+				b = uint64(r)
+			} else {
+				b, err = strconv.ParseUint(lit.Value, 0, 8)
+			}
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, byte(b))
+		}
+		return data, nil
+	case *ast.CallExpr:
+		if len(e.Args) == 1 {
+			return evalBytes(e.Args[0])
+		}
+	}
+	return nil, fmt.Errorf("unsupported expression %T", e)
+}
+
+// runInspect executes the inspect subcommand, which lists the files of
+// generated files or extracts one of them.
+func runInspect(args []string) error {
+	var extract string
+	fs := flag.NewFlagSet("bindata inspect", flag.ContinueOnError)
+	fs.StringVar(&extract, "x", "", "extract the file with this key to the standard output")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("inspect: no generated file specified")
+	}
+
+	g, err := ParseGenerated(fs.Args()...)
+	if err != nil {
+		return err
+	}
+	if extract != "" {
+		data, err := g.Read(extract)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SIZE\tSTORED\tCODEC\tKEY")
+	for _, key := range g.Keys() {
+		data, err := g.Read(key)
+		if err != nil {
+			return err
+		}
+		codec := g.Codecs[key]
+		if codec == "" {
+			codec = "-"
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", len(data), len(g.Data[key]), codec, key)
+	}
+	return w.Flush()
+}
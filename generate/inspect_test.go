@@ -0,0 +1,106 @@
+package generate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseGenerated tests reading back the files of generated files.
+func TestParseGenerated(t *testing.T) {
+	dir, in := t.TempDir(), t.TempDir()
+	want := map[string][]byte{
+		"big.txt":   []byte(strings.Repeat("compress me\n", 100)),
+		"small.txt": []byte("don't compress me"),
+	}
+	for name, data := range want {
+		if err := os.WriteFile(filepath.Join(in, name), data, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := filepath.Join(dir, "bindata.json")
+	if err := os.WriteFile(config, []byte(`{"groups": {"extra": ["big.txt"]}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "gen.go")
+	for _, args := range [][]string{{}, {"-s"}, {"-bytes-via-string", "-compress", "gzip"}} {
+		runTest(t, "", append(args, "-c", config, "-o", out, "-r", in, in)...)
+
+		g, err := ParseGenerated(out, filepath.Join(dir, "gen_extra.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keys := g.Keys(); !reflect.DeepEqual(keys, []string{"big.txt", "small.txt"}) {
+			t.Errorf("%v: unexpected keys %v", args, keys)
+		}
+		for name, data := range want {
+			got, err := g.Read(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("%v: %s: unexpected data %q", args, name, got)
+			}
+		}
+		if compressed := g.Codecs["big.txt"] == "gzip"; compressed != (len(args) == 3) {
+			t.Errorf("%v: unexpected codecs %v", args, g.Codecs)
+		}
+	}
+}
+
+// TestParseEmbedded tests reading back the files embedded with go:embed.
+func TestParseEmbedded(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "gen.go")
+	if err := runGenerate([]string{"-embed-over", "20", "-o", out, "-r", testdata, testdata}); err != nil {
+		t.Fatal(err)
+	}
+	g, err := ParseGenerated(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := g.Keys(); !reflect.DeepEqual(keys, []string{"empty", "gopher.gif", "play/bytes/11", "play/bytes/12", "play/bytes/13", "play/hello.go"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	for key, data := range g.Data {
+		want, err := os.ReadFile(filepath.Join(testdata, filepath.FromSlash(key)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, want) {
+			t.Errorf("%s: unexpected data %q", key, data)
+		}
+	}
+}
+
+// TestParseLayouts tests reading back the files of all the layouts.
+func TestParseLayouts(t *testing.T) {
+	for _, args := range [][]string{
+		{"-layout", "slice"}, {"-layout", "slice", "-s"}, {"-layout", "blob"}, {"-layout", "vars"}, {"-layout", "vars", "-s"}, {"-target", "tinygo"},
+	} {
+		out := filepath.Join(t.TempDir(), "gen.go")
+		if err := runGenerate(append(args, "-comments", "-o", out, "-r", testdata, testdata)); err != nil {
+			t.Fatal(err)
+		}
+		g, err := ParseGenerated(out)
+		if err != nil {
+			t.Errorf("%v: %v", args, err)
+			continue
+		}
+		if keys := g.Keys(); !reflect.DeepEqual(keys, []string{"empty", "gopher.gif", "play/bytes/11", "play/bytes/12", "play/bytes/13", "play/hello.go"}) {
+			t.Errorf("%v: unexpected keys %v", args, keys)
+		}
+		for key, data := range g.Data {
+			want, err := os.ReadFile(filepath.Join(testdata, filepath.FromSlash(key)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, want) {
+				t.Errorf("%v: %s: unexpected data %q", args, key, data)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package generate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// latestGo is the minor version of Go targeted when no version is given:
+// the generated code may use any feature of the language and the standard library.
+const latestGo = 1 << 30
+
+// ParseLang parses a Go version of the form "1.16" or "go1.16", optionally
+// followed by a patch number, and returns its minor version.
+// The empty string denotes the latest version.
+func ParseLang(s string) (int, error) {
+	if s == "" {
+		return latestGo, nil
+	}
+	v := strings.TrimPrefix(s, "go")
+	major, rest, _ := strings.Cut(v, ".")
+	minor, _, _ := strings.Cut(rest, ".")
+	n, err := strconv.Atoi(minor)
+	if major != "1" || err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid Go version %q", s)
+	}
+	return n, nil
+}
+
+// A langFeature is a flag whose generated code requires a minimum version of Go.
+type langFeature struct {
+	Flag  string
+	Set   bool
+	Minor int // minimum minor version of Go
+	Why   string
+}
+
+// checkLang returns an error for the first feature set that requires
+// a version of Go newer than minor.
+func checkLang(minor int, features []langFeature) error {
+	for _, f := range features {
+		if f.Set && minor < f.Minor {
+			return fmt.Errorf("%s requires Go 1.%d (%s), -lang is 1.%d", f.Flag, f.Minor, f.Why, minor)
+		}
+	}
+	return nil
+}
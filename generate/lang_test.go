@@ -0,0 +1,73 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseLang tests the parsing of Go versions.
+func TestParseLang(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+		ok   bool
+	}{
+		{"", latestGo, true},
+		{"1.16", 16, true},
+		{"go1.18", 18, true},
+		{"1.20.3", 20, true},
+		{"2.0", 0, false},
+		{"1", 0, false},
+		{"1.x", 0, false},
+		{"1.-1", 0, false},
+	}
+	for _, test := range tests {
+		got, err := ParseLang(test.s)
+		if (err == nil) != test.ok || got != test.want {
+			t.Errorf("%q: expected %d (ok %v), got %d (%v)", test.s, test.want, test.ok, got, err)
+		}
+	}
+}
+
+// TestLang tests the code generated for older versions of Go.
+func TestLang(t *testing.T) {
+	for _, args := range [][]string{
+		{"-lang", "1.15", "-fs"},
+		{"-lang", "1.19", "-meta", "exec"},
+		{"-lang", "1.19", "-readonly"},
+		{"-lang", "1.17", "-typed", "json"},
+		{"-typed", "xml"},
+		{"-lang", "one"},
+	} {
+		if err := runGenerate(append(args, "-o", filepath.Join(t.TempDir(), "out.go"), testdata)); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "out.go")
+	if err := runGenerate([]string{"-lang", "1.16", "-fs", "-compress", "gzip", "-o", out, "-r", testdata, testdata}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"strings.Cut", " any ", "unsafe."} {
+		if strings.Contains(string(b), s) {
+			t.Errorf("%s used by the code generated for Go 1.16", s)
+		}
+	}
+
+	out = filepath.Join(t.TempDir(), "out.go")
+	if err := runGenerate([]string{"-lang", "1.15", "-compress", "gzip", "-o", out, "-r", testdata, testdata}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = os.ReadFile(out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "ioutil.ReadAll") || strings.Contains(string(b), "io.ReadAll") {
+		t.Errorf("io.ReadAll used by the code generated for Go 1.15")
+	}
+}
@@ -0,0 +1,87 @@
+package generate
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// A Lock pins the SHA-256 checksums of the files to embed, indexed by key,
+// so that a change of vendored third-party assets fails the generation
+// instead of slipping into the binaries. It is stored in a lockfile
+// (bindata.lock) with a line per file, in the format of sha256sum:
+//
+//	<checksum>  <key>
+type Lock map[string]string
+
+// NewLock returns the lock of the given assets.
+func NewLock(assets map[string]*Asset) Lock {
+	l := make(Lock, len(assets))
+	for key, a := range assets {
+		sum := a.SHA256()
+		l[filepath.ToSlash(key)] = hex.EncodeToString(sum[:])
+	}
+	return l
+}
+
+// LoadLock reads a lock from a lockfile.
+func LoadLock(file string) (Lock, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	l := make(Lock)
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		sum, key, ok := strings.Cut(sc.Text(), "  ")
+		if !ok || len(sum) != 2*sha256.Size {
+			return nil, fmt.Errorf("%s:%d: invalid line %q", file, n, sc.Text())
+		}
+		l[key] = sum
+	}
+	return l, sc.Err()
+}
+
+// Write writes the lock in the format of lockfiles, sorted by key.
+// It fails if a key holds a newline, which would break the format.
+func (l Lock) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, key := range slices.Sorted(maps.Keys(l)) {
+		if strings.ContainsAny(key, "\r\n") {
+			return fmt.Errorf("cannot write %q to a lockfile: newline in the name", key)
+		}
+		fmt.Fprintf(bw, "%s  %s\n", l[key], key)
+	}
+	return bw.Flush()
+}
+
+// Diff describes the files of cur differing from the lock, by key: those
+// whose checksum changed, those which are not pinned and, unless partial
+// (e.g. with -only), the pinned files missing from cur.
+func (l Lock) Diff(cur Lock, partial bool) []string {
+	var diffs []string
+	for _, key := range slices.Sorted(maps.Keys(cur)) {
+		switch sum, ok := l[key]; {
+		case !ok:
+			diffs = append(diffs, key+": not in the lock")
+		case sum != cur[key]:
+			diffs = append(diffs, key+": checksum changed")
+		}
+	}
+	if !partial {
+		for _, key := range slices.Sorted(maps.Keys(l)) {
+			if _, ok := cur[key]; !ok {
+				diffs = append(diffs, key+": missing")
+			}
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,60 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestLock tests the lockfiles pinning the checksums of the files.
+func TestLock(t *testing.T) {
+	lock := Lock{"a": "1", "b": "2", "c": "3"}
+	diffs := lock.Diff(Lock{"a": "1", "b": "x", "d": "4"}, false)
+	if want := []string{"b: checksum changed", "d: not in the lock", "c: missing"}; !slices.Equal(diffs, want) {
+		t.Errorf("expected %q, got %q", want, diffs)
+	}
+	if diffs := lock.Diff(Lock{"a": "1"}, true); len(diffs) != 0 {
+		t.Errorf("unexpected diffs of partial files %q", diffs)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.js"), []byte("a"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	lockFile := filepath.Join(dir, "bindata.lock") // not embedded
+	args := []string{"-lock", lockFile, "-o", filepath.Join(t.TempDir(), "out.go"), "-r", dir, dir}
+	if err := runGenerate(args); !os.IsNotExist(err) {
+		t.Errorf("expected missing lockfile, got %v", err)
+	}
+	if err := runGenerate(append([]string{"-update-lock"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(lockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb  a.js\n"; string(b) != want {
+		t.Errorf("expected lockfile %q, got %q", want, b)
+	}
+	if err := runGenerate(args); err != nil {
+		t.Fatal(err)
+	}
+	if l, err := LoadLock(lockFile); err != nil || len(l) != 1 {
+		t.Errorf("unexpected lock %v: %v", l, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.js"), []byte("b"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate(args); err == nil || !strings.Contains(err.Error(), "a.js: checksum changed") {
+		t.Errorf("expected checksum error, got %v", err)
+	}
+	if err := os.WriteFile(lockFile, []byte("abc a.js\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLock(lockFile); err == nil {
+		t.Error("expected error for invalid lockfile")
+	}
+}
@@ -0,0 +1,59 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// An Event is a diagnostic of a generation: a file added, the duration of a
+// phase, a warning or an error. Events are printed on the standard error as
+// lines of text, or as JSON lines with -log=json for build systems.
+type Event struct {
+	Level   string  `json:"level"` // info, warning or error
+	Kind    string  `json:"kind"`  // file, phase, report, skip, collision, secret, pin or error
+	Message string  `json:"message"`
+	Key     string  `json:"key,omitempty"`     // key of the file concerned
+	Path    string  `json:"path,omitempty"`    // path of the file concerned
+	Size    int     `json:"size,omitempty"`    // size of the file, or of the files read
+	Files   int     `json:"files,omitempty"`   // number of files added so far
+	Phase   string  `json:"phase,omitempty"`   // walk, read, encode or write
+	Seconds float64 `json:"seconds,omitempty"` // duration of the phase
+}
+
+// A logger prints the events of a generation.
+type logger struct {
+	w    io.Writer
+	json bool // JSON lines instead of text
+}
+
+// log prints an event, on a single line.
+func (l logger) log(e Event) {
+	if l.json {
+		json.NewEncoder(l.w).Encode(e)
+		return
+	}
+	if e.Level == "warning" {
+		fmt.Fprintln(l.w, "bindata: warning:", e.Message)
+	} else {
+		fmt.Fprintln(l.w, "bindata:", e.Message)
+	}
+}
+
+// phaseEvent returns the event of the end of a phase.
+func phaseEvent(phase string, d time.Duration) Event {
+	return Event{
+		Level:   "info",
+		Kind:    "phase",
+		Message: fmt.Sprintf("%s: %v", phase, d.Round(time.Microsecond)),
+		Phase:   phase,
+		Seconds: d.Seconds(),
+	}
+}
+
+// A loggedError is an error of a generation already logged as an event.
+type loggedError struct{ error }
+
+// Unwrap returns the error of the generation.
+func (e loggedError) Unwrap() error { return e.error }
@@ -0,0 +1,62 @@
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLog tests the diagnostics printed as JSON lines with -log=json.
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	gen := newGenerator(context.Background(), nil)
+	gen.log.w = &buf
+	out := filepath.Join(t.TempDir(), "out.go")
+	if err := gen.run([]string{"-log", "json", "-v", "-progress", "-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err != nil {
+		t.Fatal(err)
+	}
+	gen.onCollision = "last"
+	if err := gen.addSource(readerSource{"play/bytes/11", strings.NewReader("x")}); err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := make(map[string]int)
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("%v: %s", err, sc.Bytes())
+		}
+		kinds[e.Kind]++
+		switch e.Kind {
+		case "file":
+			if e.Level != "info" || e.Key == "" || e.Size == 0 || e.Files == 0 {
+				t.Errorf("unexpected file event %+v", e)
+			}
+		case "phase":
+			if e.Phase == "" || e.Message == "" {
+				t.Errorf("unexpected phase event %+v", e)
+			}
+		case "collision":
+			if e.Level != "warning" || e.Key != "play/bytes/11" {
+				t.Errorf("unexpected collision event %+v", e)
+			}
+		}
+	}
+	if kinds["file"] != 4 || kinds["phase"] != 4 || kinds["report"] != 1 || kinds["collision"] != 1 {
+		t.Errorf("unexpected events %v", kinds)
+	}
+
+	// errors are logged, not to be printed again
+	if err := runGenerate([]string{"-log", "json", "-o", "-", "missing"}); !errors.As(err, new(loggedError)) {
+		t.Errorf("expected logged error, got %v", err)
+	}
+	if err := runGenerate([]string{"-log", "yaml", "missing"}); err == nil || errors.As(err, new(loggedError)) {
+		t.Errorf("expected invalid format, got %v", err)
+	}
+}
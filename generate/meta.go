@@ -0,0 +1,145 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMode is the mode of the files whose mode is not recorded.
+const defaultMode fs.FileMode = 0644
+
+// executableExts are the extensions of the executable files on Windows,
+// where files have no executable bit.
+var executableExts = map[string]bool{".exe": true, ".com": true, ".bat": true, ".cmd": true}
+
+// sourceStat returns the file information of the file of a source, if any.
+func sourceStat(src Source) (fs.FileInfo, error) {
+	switch s := src.(type) {
+	case fileSource:
+		return os.Stat(s.path)
+	case fsSource:
+		return fs.Stat(s.fsys, s.path)
+	}
+	return nil, fs.ErrNotExist
+}
+
+// sourceModTime returns the modification time of the file of a source,
+// or the zero time if it is unknown.
+func sourceModTime(src Source) time.Time {
+	fi, err := sourceStat(src)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// sourceMode returns the permissions of the file of a source, or the default
+// mode if it is unknown. On Windows, the executable bit is derived from the
+// extension of the file or a "#!" line.
+func sourceMode(src Source, data []byte) fs.FileMode {
+	fi, err := sourceStat(src)
+	if err != nil {
+		return defaultMode
+	}
+	mode := fi.Mode().Perm()
+	if runtime.GOOS == "windows" {
+		mode &^= 0111
+		if executableExts[strings.ToLower(filepath.Ext(src.Name()))] || bytes.HasPrefix(data, []byte("#!")) {
+			mode |= 0111
+		}
+	}
+	return mode
+}
+
+// metaMode returns the mode recorded for a file given the -meta policy:
+// "exec" only records whether the file is executable, "mode" records
+// all its permissions and otherwise the default mode is recorded.
+func metaMode(meta string, mode fs.FileMode) fs.FileMode {
+	switch meta {
+	case "exec":
+		if mode&0111 != 0 {
+			return 0755
+		}
+	case "mode":
+		return mode.Perm()
+	}
+	return defaultMode
+}
+
+// ParseMeta parses the comma-separated -meta policies: at most one of "exec"
+// and "mode" for the permissions, and "time" for the modification times.
+func ParseMeta(s string) (perm string, times bool, err error) {
+	if s == "" {
+		return "", false, nil
+	}
+	for _, m := range strings.Split(s, ",") {
+		switch {
+		case m == "time":
+			times = true
+		case (m == "exec" || m == "mode") && perm == "":
+			perm = m
+		default:
+			return "", false, fmt.Errorf("invalid -meta %q", s)
+		}
+	}
+	return perm, times, nil
+}
+
+// SourceDateEpoch returns the time given by the SOURCE_DATE_EPOCH environment
+// variable (https://reproducible-builds.org/specs/source-date-epoch/), or the
+// zero time if it is not set.
+func SourceDateEpoch() (time.Time, error) {
+	s := os.Getenv("SOURCE_DATE_EPOCH")
+	if s == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || sec < 0 {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q", s)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// metaTime returns the modification time recorded for a file in seconds since
+// the Unix epoch, clamped to epoch if it is not zero, or 0 if it is unknown.
+func metaTime(t, epoch time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	if !epoch.IsZero() && t.After(epoch) {
+		t = epoch
+	}
+	return t.Unix()
+}
+
+// An assetInfo is the description of a file generated with -stat.
+type assetInfo struct {
+	Size    int
+	Mode    fs.FileMode
+	ModTime int64      // in seconds since the Unix epoch, 0 if not recorded
+	Hash    string     // SHA-256 checksum as a Go array literal
+	Commit  commitInfo // last git commit of the file, with -git-meta
+}
+
+// newAssetInfo returns the description of a file, with its mode recorded
+// according to the -meta policy and its modification time if times is set.
+func newAssetInfo(a *Asset, meta string, times bool, epoch time.Time) assetInfo {
+	info := assetInfo{Size: len(a.Data), Mode: metaMode(meta, a.Mode)}
+	if times {
+		info.ModTime = metaTime(a.Time, epoch)
+	}
+	sum := a.SHA256()
+	hash := make([]string, len(sum))
+	for i, b := range sum {
+		hash[i] = fmt.Sprintf("%#02x", b)
+	}
+	info.Hash = "[32]byte{" + strings.Join(hash, ", ") + "}"
+	return info
+}
@@ -0,0 +1,173 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestMetaMode tests the modes recorded for the files.
+func TestMetaMode(t *testing.T) {
+	tests := []struct {
+		meta       string
+		mode, want os.FileMode
+	}{
+		{"exec", 0700, 0755},
+		{"exec", 0600, 0644},
+		{"mode", 0700, 0700},
+		{"mode", os.ModeSymlink | 0640, 0640},
+	}
+	for _, test := range tests {
+		if got := metaMode(test.meta, test.mode); got != test.want {
+			t.Errorf("%s %o: expected %o, got %o", test.meta, test.mode, test.want, got)
+		}
+	}
+}
+
+// TestRestore tests the restoration of the files with their permissions.
+func TestRestore(t *testing.T) {
+	in := t.TempDir()
+	for name, mode := range map[string]os.FileMode{"bin/run.sh": 0755, "a.txt": 0644, "secret": 0600} {
+		path := filepath.Join(in, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const main = `package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir, err := os.MkdirTemp("", "restore")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := bindataRestore(dir); err != nil {
+		panic(err)
+	}
+	for _, name := range []string{"a.txt", "bin/run.sh", "secret"} {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%s %v\n", name, fi.Mode())
+	}
+}
+`
+	tests := map[string]string{
+		"exec": "a.txt -rw-r--r--\nbin/run.sh -rwxr-xr-x\nsecret -rw-r--r--\n",
+		"mode": "a.txt -rw-r--r--\nbin/run.sh -rwxr-xr-x\nsecret -rw-------\n",
+	}
+	for meta, want := range tests {
+		if out := runGenerated(t, main, "-meta", meta, "-r", in, in); out != want {
+			t.Errorf("%s: unexpected modes:\n%s", meta, out)
+		}
+	}
+}
+
+// TestParseMeta tests the parsing of the -meta policies.
+func TestParseMeta(t *testing.T) {
+	tests := []struct {
+		s     string
+		perm  string
+		times bool
+		ok    bool
+	}{
+		{"", "", false, true},
+		{"exec", "exec", false, true},
+		{"mode,time", "mode", true, true},
+		{"time", "", true, true},
+		{"exec,mode", "", false, false},
+		{"owner", "", false, false},
+	}
+	for _, test := range tests {
+		perm, times, err := ParseMeta(test.s)
+		if perm != test.perm || times != test.times || (err == nil) != test.ok {
+			t.Errorf("%q: expected %q %v (ok %v), got %q %v (%v)", test.s, test.perm, test.times, test.ok, perm, times, err)
+		}
+	}
+}
+
+// TestSourceDateEpoch tests the clamping of the modification times to SOURCE_DATE_EPOCH.
+func TestSourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1000000000")
+	epoch, err := SourceDateEpoch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct{ t, want int64 }{{999999999, 999999999}, {1700000000, 1000000000}} {
+		if got := metaTime(time.Unix(test.t, 5), epoch); got != test.want {
+			t.Errorf("%d: expected %d, got %d", test.t, test.want, got)
+		}
+	}
+	if got := metaTime(time.Time{}, epoch); got != 0 {
+		t.Errorf("zero time: expected 0, got %d", got)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	if _, err := SourceDateEpoch(); err == nil {
+		t.Error("invalid SOURCE_DATE_EPOCH: expected an error")
+	}
+}
+
+// TestRestoreTimes tests the modification times recorded and restored.
+func TestRestoreTimes(t *testing.T) {
+	in := t.TempDir()
+	for name, sec := range map[string]int64{"old.txt": 500000000, "new.txt": 1700000000} {
+		path := filepath.Join(in, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, time.Unix(sec, 0), time.Unix(sec, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("SOURCE_DATE_EPOCH", "1000000000")
+	const main = `package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir, err := os.MkdirTemp("", "restore")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := bindataRestore(dir); err != nil {
+		panic(err)
+	}
+	for _, name := range []string{"new.txt", "old.txt"} {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			panic(err)
+		}
+		info, err := fs.Stat(bindataFS{}, name)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(name, fi.ModTime().Unix(), info.ModTime().Unix())
+	}
+}
+`
+	const want = "new.txt 1000000000 1000000000\nold.txt 500000000 500000000\n"
+	if out := runGenerated(t, main, "-meta", "time", "-fs", "-r", in, in); out != want {
+		t.Errorf("unexpected times:\n%s", out)
+	}
+}
@@ -0,0 +1,67 @@
+package generate
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Migration is a database migration made of SQL files named after the
+// convention shared by the popular migration libraries: a version, a
+// description and a direction, e.g. "0001_create_users.up.sql" and
+// "0001_create_users.down.sql", or "0001_create_users.sql" alone.
+type Migration struct {
+	Version     uint64
+	Description string
+	Up, Down    string // keys of the files, Down empty if there is none
+}
+
+// IsMigration reports whether the file with the given name is an SQL file.
+func IsMigration(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".sql")
+}
+
+// Migrations returns the migrations of the SQL files with the given slash
+// separated keys, by increasing version. It fails if a file has no version
+// prefix or if a version has several files for a direction.
+func Migrations(keys []string) ([]Migration, error) {
+	byVersion := make(map[uint64]*Migration)
+	for _, key := range keys {
+		base := strings.TrimSuffix(path.Base(key), path.Ext(key))
+		down := strings.HasSuffix(base, ".down")
+		base = strings.TrimSuffix(strings.TrimSuffix(base, ".down"), ".up")
+		digits := strings.IndexFunc(base, func(r rune) bool { return r < '0' || r > '9' })
+		if digits < 0 {
+			digits = len(base)
+		}
+		version, err := strconv.ParseUint(base[:digits], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: no version prefix", key)
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Description: strings.TrimLeft(base[digits:], "_-.")}
+			byVersion[version] = m
+		}
+		file := &m.Up
+		if down {
+			file = &m.Down
+		}
+		if *file != "" {
+			return nil, fmt.Errorf("migrations %s and %s have the same version %d", *file, key, version)
+		}
+		*file = key
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %s has no up file", m.Down)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
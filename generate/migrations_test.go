@@ -0,0 +1,78 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestMigrations tests the ordering and pairing of migration files.
+func TestMigrations(t *testing.T) {
+	got, err := Migrations([]string{
+		"db/10_add_index.sql",
+		"db/0002_create_posts.up.sql",
+		"db/0001_create_users.down.sql",
+		"db/0001_create_users.up.sql",
+		"db/0002_create_posts.down.sql",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Migration{
+		{1, "create_users", "db/0001_create_users.up.sql", "db/0001_create_users.down.sql"},
+		{2, "create_posts", "db/0002_create_posts.up.sql", "db/0002_create_posts.down.sql"},
+		{10, "add_index", "db/10_add_index.sql", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	for _, keys := range [][]string{
+		{"init.sql"},
+		{"1_a.up.sql", "01_b.sql"},
+		{"3_c.down.sql"},
+	} {
+		if _, err := Migrations(keys); err == nil {
+			t.Errorf("%v: expected an error", keys)
+		}
+	}
+}
+
+// TestMigrationsFlag tests the generated list of migrations and file system.
+func TestMigrationsFlag(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"0001_users.up.sql":   "CREATE TABLE users;",
+		"0001_users.down.sql": "DROP TABLE users;",
+		"0002_posts.sql":      "CREATE TABLE posts;",
+		"README.md":           "ignored",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const main = `package main
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+func main() {
+	for _, m := range bindataMigrations {
+		up, err := fs.ReadFile(bindataFS{}, m.Up)
+		fmt.Println(m.Version, m.Description, m.Down, string(up), err)
+	}
+	_, err := fs.Stat(bindataFS{}, "README.md")
+	fmt.Println(err != nil)
+}
+`
+	const want = `1 users 0001_users.down.sql CREATE TABLE users; <nil>
+2 posts  CREATE TABLE posts; <nil>
+true
+`
+	if out := runGenerated(t, main, "-migrations", "-r", dir, dir); out != want {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
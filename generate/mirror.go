@@ -0,0 +1,67 @@
+package generate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// A Mirror is the set of keys of an existing generated file (-mirror), to
+// regenerate it with exactly the same files, so that a refactor of the source
+// directories cannot silently drop embedded files.
+type Mirror struct {
+	File string
+	Keys []string // sorted slash separated keys
+}
+
+// LoadMirror reads the keys of a file generated by bindata, including those of
+// the files embedded with go:embed.
+func LoadMirror(file string) (*Mirror, error) {
+	g, err := ParseGenerated(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Mirror{File: file, Keys: g.Keys()}, nil
+}
+
+// Inputs returns the paths of the source files of the keys, resolved against
+// the root of the keys. It fails if some of them are missing or are not
+// regular files.
+func (m *Mirror) Inputs(root string) ([]string, error) {
+	var paths, missing []string
+	for _, key := range m.Keys {
+		path := filepath.Join(root, filepath.FromSlash(key))
+		if fi, err := os.Stat(path); err != nil || !fi.Mode().IsRegular() {
+			missing = append(missing, path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%d file(s) of %s not found:\n\t%s", len(missing), m.File, strings.Join(missing, "\n\t"))
+	}
+	return paths, nil
+}
+
+// Check verifies that the keys of the assets are exactly those of the mirror,
+// e.g. that none of the files was skipped by a filter or an ignore file.
+func (m *Mirror) Check(assets map[string]*Asset) error {
+	var diffs []string
+	for _, key := range m.Keys {
+		if _, ok := assets[filepath.FromSlash(key)]; !ok {
+			diffs = append(diffs, key+": missing")
+		}
+	}
+	for key := range assets {
+		if _, ok := slices.BinarySearch(m.Keys, filepath.ToSlash(key)); !ok {
+			diffs = append(diffs, filepath.ToSlash(key)+": not in "+m.File)
+		}
+	}
+	if len(diffs) > 0 {
+		slices.Sort(diffs)
+		return fmt.Errorf("%d file(s) differ from %s:\n\t%s", len(diffs), m.File, strings.Join(diffs, "\n\t"))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package generate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMirror tests regenerating a file with the keys of an existing one.
+func TestMirror(t *testing.T) {
+	dir, out := t.TempDir(), t.TempDir()
+	for _, name := range []string{"a.js", "b/c.css", "d.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig, mirrored := filepath.Join(out, "orig.go"), filepath.Join(out, "mirrored.go")
+	if err := runGenerate([]string{"-o", orig, "-r", dir, filepath.Join(dir, "a.js"), filepath.Join(dir, "b")}); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-mirror", orig, "-o", mirrored, "-r", dir}
+	if err := runGenerate(args); err != nil {
+		t.Fatal(err)
+	}
+	b1, err1 := os.ReadFile(orig)
+	b2, err2 := os.ReadFile(mirrored)
+	if err1 != nil || err2 != nil || !bytes.Equal(b1, b2) {
+		t.Errorf("expected the same file (%v, %v)", err1, err2)
+	}
+
+	m, err := LoadMirror(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets := map[string]*Asset{"a.js": {}, filepath.Join("b", "c.css"): {}, "d.txt": {}}
+	if err := m.Check(assets); err == nil || !strings.Contains(err.Error(), "d.txt: not in") {
+		t.Errorf("expected extra file error, got %v", err)
+	}
+	delete(assets, "a.js")
+	if err := m.Check(assets); err == nil || !strings.Contains(err.Error(), "a.js: missing") {
+		t.Errorf("expected missing file error, got %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "b", "c.css")); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate(args); err == nil || !strings.Contains(err.Error(), "1 file(s) of "+orig+" not found") {
+		t.Errorf("expected missing source error, got %v", err)
+	}
+	if err := runGenerate(append(args, dir)); err == nil {
+		t.Error("expected error with inputs")
+	}
+
+	// the keys of the files embedded with go:embed are mirrored too
+	embedded := filepath.Join(out, "embedded.go")
+	if err := runGenerate([]string{"-embed-over", "20", "-o", embedded, "-r", testdata, testdata}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate([]string{"-mirror", embedded, "-o", mirrored, "-r", testdata}); err != nil {
+		t.Fatal(err)
+	}
+	m, err = LoadMirror(mirrored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"empty", "gopher.gif", "play/bytes/11", "play/bytes/12", "play/bytes/13", "play/hello.go"}
+	if !reflect.DeepEqual(m.Keys, want) {
+		t.Errorf("unexpected mirrored keys %v", m.Keys)
+	}
+
+	// as are those of the other layouts
+	for _, layout := range []string{"slice", "blob", "vars"} {
+		if err := runGenerate([]string{"-layout", layout, "-o", orig, "-r", testdata, testdata}); err != nil {
+			t.Fatal(err)
+		}
+		if err := runGenerate([]string{"-layout", layout, "-mirror", orig, "-o", mirrored, "-r", testdata}); err != nil {
+			t.Fatal(err)
+		}
+		b1, err1 := os.ReadFile(orig)
+		b2, err2 := os.ReadFile(mirrored)
+		if err1 != nil || err2 != nil || !bytes.Equal(b1, b2) {
+			t.Errorf("-layout %s: expected the same file (%v, %v)", layout, err1, err2)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteFile atomically writes the output of write to the file at path.
+// The output is written to a temporary file in the same directory which is
+// renamed over the destination only if write succeeds, so that a failed run
+// never leaves a truncated file behind. The mode of an existing destination
+// file is preserved.
+func WriteFile(path string, write func(io.Writer) error) error {
+	_, err := writeFile(path, write, false)
+	return err
+}
+
+// UpdateFile is like WriteFile but leaves the file at path untouched if it
+// already holds the output of write, compared by SHA-256 checksum, so that
+// its modification time only changes with its contents. It reports whether
+// the file was written.
+func UpdateFile(path string, write func(io.Writer) error) (bool, error) {
+	return writeFile(path, write, true)
+}
+
+// writeFile writes the output of write to the file at path, atomically,
+// unless skipIdentical is set and the file already holds the output.
+func writeFile(path string, write func(io.Writer) error, skipIdentical bool) (written bool, err error) {
+	mode := os.FileMode(0644)
+	fi, err := os.Stat(path)
+	if err == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil || !written {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	h := sha256.New()
+	cw := &countingWriter{w: io.MultiWriter(tmp, h)}
+	buf := bufio.NewWriter(cw)
+	if err = write(buf); err != nil {
+		return false, err
+	}
+	if err = buf.Flush(); err != nil {
+		return false, err
+	}
+	if skipIdentical && fi != nil && fi.Mode().IsRegular() && fi.Size() == cw.n && fileSum(path) == [sha256.Size]byte(h.Sum(nil)) {
+		return false, nil
+	}
+	if err = tmp.Chmod(mode); err != nil {
+		return false, err
+	}
+	if err = tmp.Close(); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp.Name(), path)
+}
+
+// fileSum returns the SHA-256 checksum of the file at path, or zero if it
+// cannot be read.
+func fileSum(path string) (sum [sha256.Size]byte) {
+	f, err := os.Open(path)
+	if err != nil {
+		return sum
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum
+	}
+	return [sha256.Size]byte(h.Sum(nil))
+}
+
+// CheckOverwrite returns an error if the file at path exists, is not empty and
+// looks written by hand: it has neither the conventional comment marking
+// generated files nor marker. The JSON files, which cannot have comments, are
+// accepted if valid and isJSON is set.
+func CheckOverwrite(path, marker string, isJSON bool) error {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(b)) == 0 || generatedComments.Match(b) || bytes.Contains(b, []byte(marker)) || isJSON && json.Valid(b) {
+		return nil
+	}
+	return fmt.Errorf("refusing to overwrite %s, which does not look generated by bindata (use -force)", path)
+}
+
+// SameFile reports whether the paths a and b designate the same file,
+// which does not need to exist.
+func SameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA == nil && errB == nil && absA == absB {
+		return true
+	}
+	fa, errA := os.Stat(a)
+	fb, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(fa, fb)
+}
+
+// Paths is a list of paths usable as a repeatable command line flag.
+type Paths []string
+
+// String returns the paths as they would appear on the command line.
+func (ps *Paths) String() string {
+	return strings.Join(*ps, ", ")
+}
+
+// Set adds a path to the list.
+func (ps *Paths) Set(value string) error {
+	*ps = append(*ps, value)
+	return nil
+}
@@ -0,0 +1,249 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWriteFile tests that the output file is only replaced on success.
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.go")
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteFile(path, func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return errors.New("failure")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if b, _ := os.ReadFile(path); string(b) != "old" {
+		t.Errorf("file modified by failed write: %q", b)
+	}
+
+	if err := WriteFile(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "new")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "new" {
+		t.Errorf("unexpected contents %q", b)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("mode not preserved: %v %v", fi.Mode(), err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("temporary files left behind: %v", files)
+	}
+}
+
+// TestSameFile tests the comparison of file paths.
+func TestSameFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if !SameFile("a.go", filepath.Join(dir, "x", "..", "a.go")) {
+		t.Error("expected same file for equivalent paths")
+	}
+	if SameFile("a.go", "b.go") {
+		t.Error("expected different files")
+	}
+	if err := os.WriteFile("a.go", nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("a.go", "b.go"); err != nil {
+		t.Skip(err)
+	}
+	if !SameFile("a.go", "b.go") {
+		t.Error("expected same file for symbolic link")
+	}
+}
+
+// TestOutputInInputs tests that output files are never embedded.
+func TestOutputInInputs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "bindata.go")
+	for i := 0; i < 2; i++ {
+		gen := newGenerator(context.Background(), nil)
+		if err := gen.run([]string{"-o", out, "-r", dir, dir}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := gen.assets["bindata.go"]; ok || len(gen.assets) != 1 {
+			t.Errorf("run %d: unexpected assets %v", i, gen.assets)
+		}
+	}
+	if err := runGenerate([]string{"-strict", "-o", out, "-r", dir, dir}); err == nil {
+		t.Error("expected error with -strict")
+	}
+}
+
+// TestCopies tests that the copies of the output file are identical.
+func TestCopies(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	out, dup := filepath.Join(dir, "bindata.go"), filepath.Join(dir, "artifacts", "bindata.go")
+	gen := newGenerator(context.Background(), nil)
+	if err := gen.run([]string{"-o", out, "-o-copy", dup, "-r", dir, dir}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gen.assets[filepath.Join("artifacts", "bindata.go")]; ok || len(gen.assets) != 1 {
+		t.Errorf("unexpected assets %v", gen.assets)
+	}
+	a, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(dup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Error("copy differs from the output file")
+	}
+	if err := runGenerate([]string{"-o", out, "-o-copy", dup, "-per-dir-output", dir}); err == nil {
+		t.Error("expected error with -per-dir-output")
+	}
+}
+
+// TestForce tests that hand-written files are only overwritten with -force.
+func TestForce(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "main.go")
+	hand := []byte("package main\n\nfunc main() {}\n")
+	if err := os.WriteFile(out, hand, 0666); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}
+	if err := runGenerate(args); err == nil {
+		t.Error("expected error overwriting a hand-written file")
+	}
+	if b, err := os.ReadFile(out); err != nil || !bytes.Equal(b, hand) {
+		t.Errorf("hand-written file changed: %v", err)
+	}
+	if err := runGenerate(append([]string{"-force"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	// the generated file can be overwritten from now on
+	if err := runGenerate(args); err != nil {
+		t.Error(err)
+	}
+	if err := runGenerate(append([]string{"-generated", "// Custom marker."}, args...)); err != nil {
+		t.Error(err)
+	}
+	if err := runGenerate(append([]string{"-o-copy", filepath.Join(dir, "main.go"), "-o", filepath.Join(dir, "b.go")}, args[2:]...)); err == nil {
+		t.Error("expected error overwriting a file with an unknown marker")
+	}
+}
+
+// TestStdout tests that nothing but the generated code is written with -o -.
+func TestStdout(t *testing.T) {
+	dir := t.TempDir()
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+	defer func(o, e *os.File) { os.Stdout, os.Stderr = o, e }(os.Stdout, os.Stderr)
+	os.Stdout, os.Stderr = stdout, stderr
+
+	args := []string{"-v", "-progress", "-r", testdata, filepath.Join(testdata, "play", "bytes")}
+	if err := runGenerate(append([]string{"-o", "-"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate(append([]string{"-o", "-"}, "missing")); !errors.As(err, new(quietError)) {
+		t.Errorf("expected error to print on stderr, got %v", err)
+	}
+	out, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, []byte(defaultGenerated)) || !bytes.HasSuffix(out, []byte("}\n")) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if fi, err := stderr.Stat(); err != nil || fi.Size() != 0 {
+		t.Errorf("unexpected diagnostics: %v", err)
+	}
+
+	// the diagnostics are kept otherwise
+	if err := runGenerate(append([]string{"-o", filepath.Join(dir, "out.go")}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := stderr.Stat(); err != nil || fi.Size() == 0 {
+		t.Errorf("expected diagnostics: %v", err)
+	}
+}
+
+// TestUnchangedOutput tests that output files are not written again when
+// their contents are unchanged, unless -force-write.
+func TestUnchangedOutput(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.go")
+	args := []string{"-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}
+	if err := runGenerate(args); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(out, old, old); err != nil {
+		t.Fatal(err)
+	}
+	modTime := func() time.Time {
+		fi, err := os.Stat(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi.ModTime()
+	}
+
+	if err := runGenerate(args); err != nil {
+		t.Fatal(err)
+	}
+	if !modTime().Equal(old) {
+		t.Error("unchanged output file written again")
+	}
+	if err := runGenerate(append([]string{"-force-write"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	if modTime().Equal(old) {
+		t.Error("output file not written with -force-write")
+	}
+	if err := os.Chtimes(out, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenerate(append([]string{"-m", "assets"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	if modTime().Equal(old) {
+		t.Error("changed output file not written")
+	}
+
+	files, err := os.ReadDir(filepath.Dir(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("temporary files left behind: %v", files)
+	}
+}
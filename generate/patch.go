@@ -0,0 +1,171 @@
+package generate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Binary patches (made by the patch subcommand, applied by the function
+// generated with -patches) turn the contents of a file into new contents,
+// e.g. to hotfix a large embedded file with a small download instead of a new
+// binary. Like bsdiff, a patch copies the parts of the new contents found in
+// the original contents and adds the others:
+//
+//	"BDP1" <SHA-256 of the original> <SHA-256 of the new contents> <size>
+//	then, until the end: 0 <length> <bytes to add>
+//	                  or 1 <length> <offset of the bytes to copy>
+//
+// The numbers are unsigned varints. The checksums ensure that a patch is only
+// applied to the contents it was made from, and that the result is correct.
+const patchMagic = "BDP1"
+
+// patchBlock is the size of the blocks of the original contents searched in
+// the new contents.
+const patchBlock = 16
+
+// Patch operations.
+const (
+	patchAdd  = 0
+	patchCopy = 1
+)
+
+// errPatch is the error of corrupted patches.
+var errPatch = errors.New("corrupted patch")
+
+// MakePatch returns the patch turning old into new.
+func MakePatch(old, new []byte) []byte {
+	index := make(map[string]int) // offsets of the blocks of old
+	for i := 0; i+patchBlock <= len(old); i += patchBlock {
+		if _, ok := index[string(old[i:i+patchBlock])]; !ok {
+			index[string(old[i:i+patchBlock])] = i
+		}
+	}
+
+	var p bytes.Buffer
+	oldSum, newSum := sha256.Sum256(old), sha256.Sum256(new)
+	p.WriteString(patchMagic)
+	p.Write(oldSum[:])
+	p.Write(newSum[:])
+	p.Write(binary.AppendUvarint(nil, uint64(len(new))))
+	op := func(code byte, length, offset int) {
+		p.WriteByte(code)
+		p.Write(binary.AppendUvarint(nil, uint64(length)))
+		if code == patchCopy {
+			p.Write(binary.AppendUvarint(nil, uint64(offset)))
+		}
+	}
+	add := func(data []byte) {
+		if len(data) > 0 {
+			op(patchAdd, len(data), 0)
+			p.Write(data)
+		}
+	}
+
+	start := 0 // start of the bytes to add
+	for i := 0; i+patchBlock <= len(new); {
+		off, ok := index[string(new[i:i+patchBlock])]
+		if !ok {
+			i++
+			continue
+		}
+		// extend the match backwards over the bytes to add, and forwards
+		back := 0
+		for back < i-start && back < off && new[i-back-1] == old[off-back-1] {
+			back++
+		}
+		n := patchBlock
+		for i+n < len(new) && off+n < len(old) && new[i+n] == old[off+n] {
+			n++
+		}
+		add(new[start : i-back])
+		op(patchCopy, back+n, off-back)
+		i += n
+		start = i
+	}
+	add(new[start:])
+	return p.Bytes()
+}
+
+// ApplyPatch applies a patch to old, the contents it was made from,
+// and returns the new contents.
+func ApplyPatch(old, patch []byte) ([]byte, error) {
+	if len(patch) < len(patchMagic)+2*sha256.Size || string(patch[:len(patchMagic)]) != patchMagic {
+		return nil, errors.New("not a patch")
+	}
+	patch = patch[len(patchMagic):]
+	if sum := sha256.Sum256(old); !bytes.Equal(sum[:], patch[:sha256.Size]) {
+		return nil, errors.New("patch made from other contents")
+	}
+	want, p := patch[sha256.Size:2*sha256.Size], patch[2*sha256.Size:]
+	size, n := binary.Uvarint(p)
+	if n <= 0 {
+		return nil, errPatch
+	}
+	var data []byte
+	for p = p[n:]; len(p) > 0; {
+		code := p[0]
+		length, n := binary.Uvarint(p[1:])
+		if n <= 0 {
+			return nil, errPatch
+		}
+		p = p[1+n:]
+		switch {
+		case code == patchAdd && length <= uint64(len(p)):
+			data = append(data, p[:length]...)
+			p = p[length:]
+		case code == patchCopy:
+			offset, n := binary.Uvarint(p)
+			if n <= 0 || offset > uint64(len(old)) || length > uint64(len(old))-offset {
+				return nil, errPatch
+			}
+			data = append(data, old[offset:offset+length]...)
+			p = p[n:]
+		default:
+			return nil, errPatch
+		}
+	}
+	if sum := sha256.Sum256(data); uint64(len(data)) != size || !bytes.Equal(sum[:], want) {
+		return nil, errPatch
+	}
+	return data, nil
+}
+
+// runPatch runs the patch subcommand, writing the patch turning a file into
+// its new version.
+func runPatch(args []string) error {
+	var out string
+	fs := flag.NewFlagSet("bindata patch", flag.ContinueOnError)
+	fs.StringVar(&out, "o", "", "output file of the patch (default stdout)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("patch: expected the original and new versions of a file")
+	}
+	old, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	new, err := os.ReadFile(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	patch := MakePatch(old, new)
+	if data, err := ApplyPatch(old, patch); err != nil || !bytes.Equal(data, new) {
+		return fmt.Errorf("patch: cannot verify the patch of %s", fs.Arg(1))
+	}
+	if out == "" {
+		_, err := os.Stdout.Write(patch)
+		return err
+	}
+	return WriteFile(out, func(w io.Writer) error {
+		_, err := w.Write(patch)
+		return err
+	})
+}
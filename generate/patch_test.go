@@ -0,0 +1,92 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPatch tests making and applying binary patches.
+func TestPatch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	old := make([]byte, 100000)
+	rnd.Read(old)
+	edited := bytes.Clone(old)
+	copy(edited[5000:], "hotfix")
+	edited = append(edited[:50000], append([]byte("inserted"), edited[60000:]...)...)
+	for _, c := range []struct {
+		name     string
+		old, new []byte
+		max      int // maximum size of the patch
+	}{
+		{"empty", nil, nil, 80},
+		{"same", old, old, 100},
+		{"edited", old, edited, 200},
+		{"appended", old, append(bytes.Clone(old), "more"...), 100},
+		{"new", []byte("abc"), []byte("0123456789abcdefghij0123456789"), 120},
+	} {
+		patch := MakePatch(c.old, c.new)
+		if len(patch) > c.max {
+			t.Errorf("%s: patch of %d bytes", c.name, len(patch))
+		}
+		data, err := ApplyPatch(c.old, patch)
+		if err != nil || !bytes.Equal(data, c.new) {
+			t.Errorf("%s: unexpected result (%v)", c.name, err)
+		}
+	}
+
+	patch := MakePatch(old, edited)
+	if _, err := ApplyPatch(edited, patch); err == nil {
+		t.Error("expected error applying to other contents")
+	}
+	for _, corrupted := range [][]byte{patch[:70], append(bytes.Clone(patch), 0), []byte("BDP0")} {
+		if _, err := ApplyPatch(old, corrupted); err == nil {
+			t.Errorf("expected error applying corrupted patch %.8q", corrupted)
+		}
+	}
+}
+
+// TestPatches tests the generated function applying patches.
+func TestPatches(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	data, err := bindataPatch("play/bytes/11", %#v)
+	fmt.Printf("%%s %%v|", data, err)
+	_, err = bindataPatch("play/bytes/12", %#v)
+	fmt.Println(err)
+}
+`
+	patch := MakePatch([]byte("10+1 bytes!"), []byte("10+1 bytes, patched!"))
+	src := fmt.Sprintf(main, patch, patch)
+	for _, flags := range [][]string{nil, {"-s"}, {"-compress", "gzip"}, {"-layout", "slice"}} {
+		args := append(flags, "-patches", "-r", testdata, filepath.Join(testdata, "play", "bytes"))
+		want := "10+1 bytes, patched! <nil>|bindata: patch made from other contents of play/bytes/12\n"
+		if out := runGenerated(t, src, args...); out != want {
+			t.Errorf("%v: unexpected output:\n%s", flags, out)
+		}
+	}
+
+	dir := t.TempDir()
+	for name, data := range map[string]string{"old": "10+1 bytes!", "new": "10+1 bytes, patched!"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := filepath.Join(dir, "delta")
+	if err := runPatch([]string{"-o", out, filepath.Join(dir, "old"), filepath.Join(dir, "new")}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(out); err != nil || !bytes.Equal(b, patch) {
+		t.Errorf("unexpected patch %q (%v)", b, err)
+	}
+	if err := runGenerate([]string{"-patches", "-hash-names", testdata}); err == nil || !strings.Contains(err.Error(), "-patches") {
+		t.Errorf("expected error with -hash-names, got %v", err)
+	}
+}
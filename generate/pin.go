@@ -0,0 +1,131 @@
+package generate
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+)
+
+// The pin subcommand records the version of bindata a team generates with in
+// the configuration file, so that the generation fails when run by another
+// version, whose output may differ, rather than producing spurious diffs.
+
+// modulePath is the path of the module of bindata, as installed by go install.
+const modulePath = "github.com/simleb/bindata"
+
+// develVersion is the version of the builds of bindata which are neither
+// installed at a version nor built from a clean VCS checkout.
+const develVersion = "(devel)"
+
+// toolVersion returns the version of the running bindata; a variable for tests.
+var toolVersion = ToolVersion
+
+// ToolVersion returns the version of the running bindata: the version of its
+// module if installed with go install path@version, otherwise the VCS revision
+// it was built from, or "(devel)" if it is unknown or the checkout was modified.
+func ToolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return develVersion
+	}
+	if v := info.Main.Version; v != "" && v != develVersion {
+		return v
+	}
+	var revision string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			if s.Value == "true" {
+				return develVersion
+			}
+		}
+	}
+	if revision == "" {
+		return develVersion
+	}
+	return revision
+}
+
+// checkPin returns an error explaining how to resolve the mismatch if the
+// version pinned by the configuration file is not the running version.
+func checkPin(configFile, pinned, running string) error {
+	if pinned == "" || pinned == running {
+		return nil
+	}
+	return fmt.Errorf("%s pins bindata %s, but this is %s, whose output may differ:\n"+
+		"\tinstall the pinned version: bindata pin -install -c %[1]s (or go install %[4]s@%[2]s)\n"+
+		"\tor pin this version for the team: bindata pin -c %[1]s", configFile, pinned, running, modulePath)
+}
+
+// Pin records version as the version of bindata required by the configuration
+// file at path, which is created if it does not exist. The other fields are
+// kept, the keys being sorted.
+func Pin(path, version string) error {
+	fields := make(map[string]json.RawMessage)
+	if _, err := LoadConfig(path); err == nil {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	fields["version"], _ = json.Marshal(version)
+	b, err := json.MarshalIndent(fields, "", "\t")
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%s\n", b)
+		return err
+	})
+}
+
+// runPin runs the pin subcommand.
+func runPin(args []string) error {
+	var configFile string
+	var install bool
+	fs := flag.NewFlagSet("bindata pin", flag.ContinueOnError)
+	fs.StringVar(&configFile, "c", "bindata.json", "configuration file")
+	fs.BoolVar(&install, "install", false, "install the pinned version with go install instead of pinning")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if install {
+		c, err := LoadConfig(configFile)
+		if err != nil {
+			return err
+		}
+		if c.Version == "" {
+			return fmt.Errorf("%s pins no version of bindata", configFile)
+		}
+		cmd := exec.Command("go", "install", modulePath+"@"+c.Version)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		return cmd.Run()
+	}
+	version := toolVersion()
+	switch fs.NArg() {
+	case 0:
+		if version == develVersion {
+			return fmt.Errorf("cannot pin a development build: give the version to pin, or install a release (go install %s@latest)", modulePath)
+		}
+	case 1:
+		version = fs.Arg(0)
+	default:
+		return fmt.Errorf("pin: too many arguments")
+	}
+	if err := Pin(configFile, version); err != nil {
+		return err
+	}
+	fmt.Printf("pinned bindata %s in %s\n", version, configFile)
+	return nil
+}
@@ -0,0 +1,49 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPin tests the pinning of the version of bindata.
+func TestPin(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "bindata.json")
+	if err := os.WriteFile(config, []byte(`{"budget": "1MB"}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := Pin(config, "v1.2.0"); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(config)
+	if err != nil || c.Version != "v1.2.0" || c.Budget.String() != "1MB" {
+		t.Fatalf("unexpected configuration %+v (%v)", c, err)
+	}
+	if err := Pin(filepath.Join(t.TempDir(), "new.json"), "v1.2.0"); err != nil {
+		t.Error(err)
+	}
+
+	defer func(v func() string) { toolVersion = v }(toolVersion)
+	args := []string{"-c", config, "-o", filepath.Join(t.TempDir(), "bindata.go"), "-r", testdata, testdata}
+	toolVersion = func() string { return "v1.2.0" }
+	if err := runGenerate(args); err != nil {
+		t.Error(err)
+	}
+	toolVersion = func() string { return "v1.3.0" }
+	if err := runGenerate(args); err == nil || !strings.Contains(err.Error(), "bindata pin -install -c "+config) {
+		t.Errorf("expected pin error, got %v", err)
+	}
+	toolVersion = func() string { return develVersion }
+	var buf bytes.Buffer
+	gen := newGenerator(context.Background(), nil)
+	gen.log.w = &buf
+	if err := gen.run(args); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "warning: ") {
+		t.Errorf("expected a warning, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,46 @@
+package generate
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// PackageName infers the name of the Go package in dir from the package clause
+// of its source files, ignoring test files.
+// If the directory contains no Go files, the name is derived from the name of
+// the directory.
+func PackageName(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+		return f.Name.Name, nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(abs))
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "assets" + name
+	}
+	return name, nil
+}
@@ -0,0 +1,32 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPackageName tests the inference of package names.
+func TestPackageName(t *testing.T) {
+	name, err := PackageName(filepath.Join(testdata, "play"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "main" {
+		t.Errorf("expected main, got %s", name)
+	}
+
+	dir := filepath.Join(t.TempDir(), "3D-Models")
+	if err := os.Mkdir(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "x_test.go"), []byte("package x_test\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if name, err = PackageName(dir); err != nil {
+		t.Fatal(err)
+	}
+	if name != "assets3dmodels" {
+		t.Errorf("expected assets3dmodels, got %s", name)
+	}
+}
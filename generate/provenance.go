@@ -0,0 +1,103 @@
+package generate
+
+import (
+	"encoding/json"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Provenance links the files of a generation to their sources, e.g. for
+// audit pipelines. It is written to a sidecar file (-provenance), so that none
+// of its data leaks into the generated code.
+type Provenance []FileProvenance
+
+// A FileProvenance is the source of a file of a provenance.
+type FileProvenance struct {
+	Key     string `json:"key"`
+	Path    string `json:"path,omitempty"`   // absolute path of the source file
+	Commit  string `json:"commit,omitempty"` // commit checked out in the git repository of the source file
+	ModTime string `json:"mtime,omitempty"`  // modification time of the source, in RFC 3339 format
+}
+
+// NewProvenance returns the provenance of the given assets, stored under the
+// content-addressed keys of hashed if they have one. The path and commit are
+// only known for files read from the file system, and the commit if git is
+// installed and the file is in a repository.
+func NewProvenance(assets map[string]*Asset, hashed map[string]string) (Provenance, error) {
+	commits := make(map[string]string) // commits by directory
+	p := make(Provenance, 0, len(assets))
+	for key, a := range assets {
+		f := FileProvenance{Key: filepath.ToSlash(key)}
+		if h, ok := hashed[key]; ok {
+			f.Key = h
+		}
+		if a.Path != "" {
+			path, err := filepath.Abs(a.Path)
+			if err != nil {
+				return nil, err
+			}
+			f.Path = path
+			dir := filepath.Dir(path)
+			commit, ok := commits[dir]
+			if !ok {
+				commit = gitCommit(dir)
+				commits[dir] = commit
+			}
+			f.Commit = commit
+		}
+		if !a.Time.IsZero() {
+			f.ModTime = a.Time.UTC().Format(time.RFC3339)
+		}
+		p = append(p, f)
+	}
+	sort.Slice(p, func(i, j int) bool { return p[i].Key < p[j].Key })
+	return p, nil
+}
+
+// gitCommit returns the commit checked out in the git repository containing
+// dir, or an empty string if there is none.
+func gitCommit(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// A commitInfo describes the last git commit of a file (-git-meta).
+type commitInfo struct {
+	Hash   string
+	Time   int64 // committer date in seconds since the Unix epoch
+	Author string
+}
+
+// gitLastCommit returns the last git commit of the file at path, or the zero
+// commitInfo if there is none: if the file was never committed, is not in a
+// repository or git is not installed.
+func gitLastCommit(path string) commitInfo {
+	out, err := exec.Command("git", "-C", filepath.Dir(path), "log", "-1", "--format=%H%x00%ct%x00%an", "--", filepath.Base(path)).Output()
+	if err != nil {
+		return commitInfo{}
+	}
+	fields := strings.Split(strings.TrimSuffix(string(out), "\n"), "\x00")
+	if len(fields) != 3 {
+		return commitInfo{}
+	}
+	t, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return commitInfo{}
+	}
+	return commitInfo{Hash: fields[0], Time: t, Author: fields[2]}
+}
+
+// WriteJSON writes the provenance as an indented JSON array.
+func (p Provenance) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(p)
+}
@@ -0,0 +1,41 @@
+package generate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestProvenance tests the sidecar file linking the keys to their sources.
+func TestProvenance(t *testing.T) {
+	dir := t.TempDir()
+	out, prov := filepath.Join(dir, "bindata.go"), filepath.Join(dir, "prov.json")
+	if err := runGenerate([]string{"-o", out, "-provenance", prov, "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(prov)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p Provenance
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatal(err)
+	}
+	if len(p) != 3 || p[0].Key != "play/bytes/11" {
+		t.Fatalf("unexpected provenance %v", p)
+	}
+	if want := filepath.Join(testdata, "play", "bytes", "11"); p[0].Path != want {
+		t.Errorf("expected path %s, got %s", want, p[0].Path)
+	}
+	if p[0].ModTime == "" {
+		t.Error("missing modification time")
+	}
+	if c := p[0].Commit; c != "" && len(c) != 40 {
+		t.Errorf("invalid commit %q", c)
+	}
+	if generated, err := os.ReadFile(out); err != nil || strings.Contains(string(generated), testdata) {
+		t.Errorf("source paths leaked into the generated file (%v)", err)
+	}
+}
@@ -0,0 +1,99 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsURL reports whether a command line input is the URL of a remote file.
+func IsURL(arg string) bool {
+	return strings.HasPrefix(arg, "https://") || strings.HasPrefix(arg, "http://")
+}
+
+// urlSource is a file downloaded at generation time.
+type urlSource struct {
+	name, url string
+	sum       string          // expected SHA-256 of the contents in hexadecimal, if pinned
+	ctx       context.Context // context of the download, that of the generation if nil
+}
+
+func (s urlSource) Name() string { return s.name }
+
+// Open downloads the file and checks its hash if it is pinned.
+func (s urlSource) Open() (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", s.url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", s.url, err)
+	}
+	if s.sum != "" {
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); got != s.sum {
+			return nil, fmt.Errorf("%s: sha256 mismatch: expected %s, got %s", s.url, s.sum, got)
+		}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// URLSource returns a source downloading the file at url, stored under name.
+// If sum is not empty, it is the expected SHA-256 of the contents in
+// hexadecimal, and the download fails if it does not match.
+func URLSource(name, url, sum string) Source {
+	return urlSource{filepath.FromSlash(name), url, strings.ToLower(sum), nil}
+}
+
+// AddURL adds the file downloaded from a URL to the assets. The key and the
+// expected SHA-256 of the contents can be given as parameters of the fragment
+// of the URL, which is not sent to the server:
+//
+//	https://example.com/schema.json#key=schemas/v1.json&sha256=...
+//
+// The key defaults to the last element of the path of the URL.
+func (gen *generator) AddURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	params, err := url.ParseQuery(u.Fragment)
+	if err != nil {
+		return fmt.Errorf("%s: invalid fragment: %v", rawURL, err)
+	}
+	for p := range params {
+		if p != "key" && p != "sha256" {
+			return fmt.Errorf("%s: unknown parameter %q", rawURL, p)
+		}
+	}
+	u.Fragment = ""
+	name := params.Get("key")
+	if name == "" {
+		name = path.Base(u.Path)
+		if name == "/" || name == "." {
+			return fmt.Errorf("%s: cannot derive a key, use #key=name", rawURL)
+		}
+	}
+	sum := params.Get("sha256")
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != 0 && len(sum) != 2*sha256.Size {
+		return fmt.Errorf("%s: invalid sha256 %q", rawURL, sum)
+	}
+	return gen.addSource(URLSource(name, u.String(), sum))
+}
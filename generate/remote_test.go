@@ -0,0 +1,84 @@
+package generate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAddURL tests adding files downloaded from URLs.
+func TestAddURL(t *testing.T) {
+	gen := newGenerator(context.Background(), nil)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/schema.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"type": "object"}`))
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256([]byte(`{"type": "object"}`))
+	pin := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		url, key, err string
+	}{
+		{"/v1/schema.json", "schema.json", ""},
+		{"/v1/schema.json#key=schemas/v1.json&sha256=" + pin, "schemas/v1.json", ""},
+		{"/v1/schema.json#sha256=" + strings.ToUpper(pin), "schema.json", ""},
+		{"/v1/schema.json#sha256=" + strings.Repeat("0", 64), "", "sha256 mismatch"},
+		{"/v1/schema.json#sha256=1234", "", "invalid sha256"},
+		{"/v1/schema.json#size=3", "", "unknown parameter"},
+		{"/missing.json", "", "404 Not Found"},
+		{"/", "", "cannot derive a key"},
+	}
+	for _, test := range tests {
+		gen.assets = make(map[string]*Asset)
+		err := gen.AddURL(srv.URL + test.url)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected error %q, got %v", test.url, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.url, err)
+			continue
+		}
+		if a, ok := gen.assets[test.key]; !ok || string(a.Data) != `{"type": "object"}` {
+			t.Errorf("%s: expected key %s, got %v", test.url, test.key, gen.assets)
+		}
+	}
+}
+
+// TestURLSource tests generations embedding files downloaded by sources.
+func TestURLSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"type": "object"}`))
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256([]byte(`{"type": "object"}`))
+	pin := hex.EncodeToString(sum[:])
+
+	out := filepath.Join(t.TempDir(), "gen.go")
+	if err := Generate(context.Background(), []string{"-o", out}, nil, URLSource("schemas/v1.json", srv.URL+"/v1/schema.json", strings.ToUpper(pin))); err != nil {
+		t.Fatal(err)
+	}
+	g, err := ParseGenerated(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := g.Read("schemas/v1.json"); err != nil || string(data) != `{"type": "object"}` {
+		t.Errorf("expected the downloaded file, got %q (%v)", data, err)
+	}
+
+	err = Generate(context.Background(), []string{"-o", out}, nil, URLSource("schemas/v1.json", srv.URL+"/v1/schema.json", strings.Repeat("0", 64)))
+	if err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Errorf("expected a sha256 mismatch, got %v", err)
+	}
+}
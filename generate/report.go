@@ -0,0 +1,64 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A Report summarizes the files of a generation, e.g. to track their sizes over time.
+type Report struct {
+	Files   int          `json:"files"`
+	Size    Size         `json:"size"`    // total size of the files
+	Stored  Size         `json:"stored"`  // total size stored, after compression
+	Binary  Size         `json:"binary"`  // estimated contribution to the size of binaries
+	Largest []FileReport `json:"largest"` // largest files by decreasing size
+}
+
+// A FileReport is the size of a file of a report.
+type FileReport struct {
+	Key    string `json:"key"`
+	Size   Size   `json:"size"`
+	Stored Size   `json:"stored"` // size stored, after compression
+}
+
+// entryOverhead is the estimated size in binaries of the entry of a file
+// besides its key and contents: the headers of the key and the data.
+const entryOverhead = 40
+
+// reportLargest is the number of largest files listed in a report.
+const reportLargest = 10
+
+// NewReport returns the report of the given files.
+func NewReport(files []FileReport) *Report {
+	r := &Report{Files: len(files)}
+	for _, f := range files {
+		r.Size += f.Size
+		r.Stored += f.Stored
+		r.Binary += f.Stored + Size(len(f.Key)+entryOverhead)
+	}
+	r.Largest = append([]FileReport{}, files...)
+	sort.Slice(r.Largest, func(i, j int) bool {
+		if r.Largest[i].Size != r.Largest[j].Size {
+			return r.Largest[i].Size > r.Largest[j].Size
+		}
+		return r.Largest[i].Key < r.Largest[j].Key
+	})
+	if len(r.Largest) > reportLargest {
+		r.Largest = r.Largest[:reportLargest]
+	}
+	return r
+}
+
+// String summarizes the report on a single line.
+func (r *Report) String() string {
+	return fmt.Sprintf("%d files, %v (%v stored, about %v in binaries)", r.Files, r.Size, r.Stored, r.Binary)
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(r)
+}
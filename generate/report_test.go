@@ -0,0 +1,43 @@
+package generate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestReport tests the summary of the sizes of the files.
+func TestReport(t *testing.T) {
+	var files []FileReport
+	for i, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		files = append(files, FileReport{key, Size(100 * (i + 1)), Size(10 * (i + 1))})
+	}
+	r := NewReport(files)
+	if r.Files != 12 || r.Size != 7800 || r.Stored != 780 || r.Binary != 780+12*(1+entryOverhead) {
+		t.Errorf("unexpected totals: %+v", r)
+	}
+	if len(r.Largest) != reportLargest || r.Largest[0].Key != "l" || r.Largest[9].Key != "c" {
+		t.Errorf("unexpected largest files: %v", r.Largest)
+	}
+	if got, want := r.String(), "12 files, 7.8KB (780B stored, about 1.272KB in binaries)"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.json")
+	if err := runGenerate([]string{"-o", filepath.Join(dir, "out.go"), "-report", report, "-r", testdata, filepath.Join(testdata, "play")}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Report
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Files != 4 || got.Size != got.Stored || got.Largest[0].Key != "play/hello.go" {
+		t.Errorf("unexpected report:\n%s", b)
+	}
+}
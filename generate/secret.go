@@ -0,0 +1,64 @@
+package generate
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// With -secret-scan, the files to embed are checked for secrets before they
+// end up in distributable binaries: their names against those of files
+// holding secrets, and their contents against the formats of common secrets.
+
+// secretPatterns are the formats of common secrets, by description.
+var secretPatterns = []struct {
+	desc string
+	re   *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+	{"AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret access key", regexp.MustCompile(`(?i)aws_?secret_?access_?key\s*["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+}
+
+// secretFile returns the description of the kind of file holding secrets the
+// slash separated key names, or an empty string if it does not look like one.
+func secretFile(key string) string {
+	base := path.Base(key)
+	switch ext := path.Ext(base); {
+	case base == ".env" || strings.HasPrefix(base, ".env.") && !templateExts[ext]:
+		return ".env file"
+	case base == ".netrc" || base == ".pgpass" || base == ".git-credentials":
+		return "credentials file"
+	case ext != ".pub" && sshKeyNames[strings.TrimSuffix(base, ext)]:
+		return "SSH private key"
+	case ext == ".p12" || ext == ".pfx":
+		return "PKCS #12 key store"
+	}
+	return ""
+}
+
+// templateExts are the extensions of the templates of .env files, which are
+// committed on purpose.
+var templateExts = map[string]bool{".example": true, ".sample": true, ".template": true, ".dist": true}
+
+// sshKeyNames are the default names of SSH private keys.
+var sshKeyNames = map[string]bool{"id_rsa": true, "id_dsa": true, "id_ecdsa": true, "id_ed25519": true}
+
+// ScanSecrets returns the descriptions of the secrets the file with the
+// slash separated key and the given contents seems to hold, by its name and
+// then by its contents, or nil if there are none.
+func ScanSecrets(key string, data []byte) []string {
+	var found []string
+	if desc := secretFile(key); desc != "" {
+		found = append(found, desc)
+	}
+	for _, p := range secretPatterns {
+		if p.re.Match(data) {
+			found = append(found, p.desc)
+		}
+	}
+	return found
+}
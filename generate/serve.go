@@ -0,0 +1,279 @@
+package generate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A server runs generations on demand over HTTP.
+type server struct {
+	args []string // command line arguments of the generations
+
+	mu       sync.Mutex
+	status   Status
+	manifest []ManifestEntry
+	inputs   []string   // paths given on the command line of the last generation
+	cache    *fileCache // contents of the input files, kept between generations
+}
+
+// newServer returns a server running generations with the given command
+// line arguments.
+func newServer(args []string) *server {
+	return &server{args: args, cache: &fileCache{next: make(map[string]cachedFile)}}
+}
+
+// A fileCache holds the contents of the files read by the generations of a
+// server, so that the files unchanged since the previous generation are not
+// read again. It only keeps the files read or reused by the last generation.
+type fileCache struct {
+	mu   sync.Mutex
+	last map[string]cachedFile // files of the previous generations, by path
+	next map[string]cachedFile // files of the current generation, by path
+}
+
+// A cachedFile is the contents of a file with the size and modification time
+// it had when it was read.
+type cachedFile struct {
+	size    int64
+	modTime time.Time
+	data    []byte
+}
+
+// get returns the contents of the file at path, if it is cached and its size
+// and modification time are those of fi.
+func (c *fileCache) get(path string, fi fs.FileInfo) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.next[path]
+	if !ok {
+		f, ok = c.last[path]
+	}
+	if !ok || f.size != fi.Size() || !f.modTime.Equal(fi.ModTime()) {
+		return nil, false
+	}
+	c.next[path] = f
+	return f.data, true
+}
+
+// put caches the contents of the file at path, described by fi.
+func (c *fileCache) put(path string, fi fs.FileInfo, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next[path] = cachedFile{fi.Size(), fi.ModTime(), data}
+}
+
+// rotate ends a generation, dropping the files it did not use.
+func (c *fileCache) rotate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last, c.next = c.next, make(map[string]cachedFile)
+}
+
+// A Status reports the outcome of a generation.
+type Status struct {
+	Generation int       `json:"generation"` // number of generations run
+	Time       time.Time `json:"time"`
+	Duration   string    `json:"duration"`
+	Error      string    `json:"error,omitempty"`
+	Files      int       `json:"files"`
+	Size       int64     `json:"size"`
+}
+
+// A ManifestEntry describes an embedded file.
+type ManifestEntry struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// runServe executes the serve subcommand until ctx is done.
+func runServe(ctx context.Context, args []string) error {
+	var addr string
+	var watch time.Duration
+	fs := flag.NewFlagSet("bindata serve", flag.ContinueOnError)
+	fs.StringVar(&addr, "addr", "localhost:7878", "address to listen on (the generations write files: only expose it to trusted clients)")
+	fs.DurationVar(&watch, "watch", 0, "interval at which the inputs are polled for changes (0 disables polling)")
+	own, rest := serveFlags(fs, args)
+	if err := parseFlags(fs, own); err != nil {
+		return err
+	}
+
+	s := newServer(rest)
+	s.generate(ctx)
+	if watch > 0 {
+		go s.watch(ctx, watch)
+	}
+	log.Printf("bindata: listening on %s", addr)
+	srv := &http.Server{Addr: addr, Handler: s, BaseContext: func(net.Listener) context.Context { return ctx }}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return ctx.Err()
+}
+
+// serveFlags splits args into the flags of fs, with their values, and the
+// arguments of the generations: the flags of fs can be mixed with those of
+// the generations, up to a -- terminator kept for the generations.
+func serveFlags(fs *flag.FlagSet, args []string) (own, rest []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return own, append(rest, args[i:]...)
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || fs.Lookup(name) == nil {
+			rest = append(rest, arg)
+			continue
+		}
+		own = append(own, arg)
+		if !hasValue && i+1 < len(args) {
+			own = append(own, args[i+1])
+			i++
+		}
+	}
+	return own, rest
+}
+
+// generate runs a generation, stopped once ctx is done, and records its status
+// and manifest.
+func (s *server) generate(ctx context.Context) Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	gen := newGenerator(ctx, nil)
+	gen.cache = s.cache
+	err := gen.run(s.args)
+	s.cache.rotate()
+	s.inputs = gen.inputs
+	s.status = Status{
+		Generation: s.status.Generation + 1,
+		Time:       start,
+		Duration:   time.Since(start).String(),
+	}
+	if err != nil {
+		s.status.Error = err.Error()
+		log.Printf("bindata: %v", err)
+		return s.status
+	}
+	s.manifest = Manifest(gen.assets)
+	s.status.Files = len(s.manifest)
+	for _, e := range s.manifest {
+		s.status.Size += e.Size
+	}
+	return s.status
+}
+
+// lastInputs returns the paths given on the command line of the last generation.
+func (s *server) lastInputs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inputs
+}
+
+// watch regenerates the output whenever the inputs change, until ctx is done.
+func (s *server) watch(ctx context.Context, interval time.Duration) {
+	last := fingerprint(s.lastInputs())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if fp := fingerprint(s.lastInputs()); fp != last {
+			last = fp
+			s.generate(ctx)
+		}
+	}
+}
+
+// ServeHTTP handles the /generate, /status and /manifest endpoints.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var v interface{}
+	switch r.URL.Path {
+	case "/generate":
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		v = s.generate(r.Context()) // canceled if the client goes away
+	case "/status":
+		s.mu.Lock()
+		v = s.status
+		s.mu.Unlock()
+	case "/manifest":
+		s.mu.Lock()
+		v = s.manifest
+		s.mu.Unlock()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	enc.Encode(v)
+}
+
+// Manifest lists the assets sorted by name, with their sizes and hashes.
+// The hashes of the assets streamed from their source files (-max-mem) are
+// computed from these files, and left empty if they cannot be read.
+func Manifest(assets map[string]*Asset) []ManifestEntry {
+	m := make([]ManifestEntry, 0, len(assets))
+	for _, a := range assets {
+		e := ManifestEntry{Name: filepath.ToSlash(a.Name), Size: int64(a.Len())}
+		if !a.Lazy {
+			sum := a.SHA256()
+			e.SHA256 = hex.EncodeToString(sum[:])
+		} else if f, err := os.Open(a.Path); err == nil {
+			h := sha256.New()
+			if _, err := io.Copy(h, f); err == nil {
+				e.SHA256 = hex.EncodeToString(h.Sum(nil))
+			}
+			f.Close()
+		}
+		m = append(m, e)
+	}
+	sort.Slice(m, func(i, j int) bool { return m[i].Name < m[j].Name })
+	return m
+}
+
+// fingerprint returns a hash of the names, sizes and modification times
+// of the files under the given paths.
+func fingerprint(paths []string) uint64 {
+	h := fnv.New64a()
+	for _, path := range paths {
+		filepath.Walk(path, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				fmt.Fprintf(h, "%s: %v\n", path, err)
+				return nil
+			}
+			fmt.Fprintf(h, "%s %d %d\n", path, fi.Size(), fi.ModTime().UnixNano())
+			return nil
+		})
+	}
+	return h.Sum64()
+}
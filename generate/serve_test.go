@@ -0,0 +1,124 @@
+package generate
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestServe tests the endpoints of the server mode.
+func TestServe(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "gen.go")
+	s := newServer([]string{"-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")})
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/generate", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /generate: expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("POST", "/generate", nil))
+	var status Status
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Generation != 1 || status.Error != "" || status.Files != 3 || status.Size != 36 {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Error(err)
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/manifest", nil))
+	var manifest []ManifestEntry
+	if err := json.NewDecoder(w.Body).Decode(&manifest); err != nil {
+		t.Fatal(err)
+	}
+	want := ManifestEntry{"play/bytes/11", 11, "eab36655b67aa49cfd1e046fa37128203ca04f78e25fff38b2bc4b5b51ab702a"}
+	if len(manifest) != 3 || manifest[0] != want {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+
+	if fingerprint(s.lastInputs()) != fingerprint([]string{filepath.Join(testdata, "play", "bytes")}) {
+		t.Error("inputs not recorded")
+	}
+
+	// the generation stops when the client goes away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("POST", "/generate", nil).WithContext(ctx))
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Generation != 2 || status.Error != context.Canceled.Error() {
+		t.Errorf("unexpected status of a canceled generation %+v", status)
+	}
+
+	// the files streamed with -max-mem are listed with their sizes and hashes
+	s = newServer([]string{"-o", out, "-max-mem", "1", "-r", testdata, filepath.Join(testdata, "play", "bytes")})
+	s.generate(context.Background())
+	if len(s.manifest) != 3 || s.manifest[0] != want {
+		t.Errorf("unexpected manifest with -max-mem %+v", s.manifest)
+	}
+}
+
+// TestServeCache tests that the files unchanged between generations are
+// not read again.
+func TestServeCache(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(file, []byte("a"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	s := newServer([]string{"-o", filepath.Join(t.TempDir(), "gen.go"), "-r", dir, dir})
+	if status := s.generate(context.Background()); status.Error != "" {
+		t.Fatal(status.Error)
+	}
+	// the cached contents are used as long as the size and time are the same
+	s.cache.last[file] = cachedFile{s.cache.last[file].size, s.cache.last[file].modTime, []byte("b")}
+	s.generate(context.Background())
+	if want := (ManifestEntry{"a.txt", 1, "3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d"}); s.manifest[0] != want {
+		t.Errorf("cached contents not used: %+v", s.manifest)
+	}
+	if err := os.Chtimes(file, time.Time{}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	s.generate(context.Background())
+	if want := (ManifestEntry{"a.txt", 1, "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"}); s.manifest[0] != want {
+		t.Errorf("modified file not read again: %+v", s.manifest)
+	}
+
+	// the files removed from the inputs leave the cache
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	s.generate(context.Background())
+	if len(s.cache.last) != 0 {
+		t.Errorf("removed file still cached: %v", s.cache.last)
+	}
+}
+
+// TestServeFlags tests the split of the flags of the server from those of
+// the generations.
+func TestServeFlags(t *testing.T) {
+	fs := flag.NewFlagSet("bindata serve", flag.ContinueOnError)
+	fs.String("addr", "", "")
+	fs.Duration("watch", 0, "")
+	own, rest := serveFlags(fs, []string{"-addr", ":1", "-o", "out.go", "-watch=1s", "static", "--", "-addr"})
+	if want := []string{"-addr", ":1", "-watch=1s"}; !slices.Equal(own, want) {
+		t.Errorf("expected server flags %q, got %q", want, own)
+	}
+	if want := []string{"-o", "out.go", "static", "--", "-addr"}; !slices.Equal(rest, want) {
+		t.Errorf("expected generation arguments %q, got %q", want, rest)
+	}
+}
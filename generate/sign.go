@@ -0,0 +1,101 @@
+package generate
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// With -override-key, the generated code only lets a zip archive override the
+// embedded files if it is signed by the private key of the given public key:
+// its Ed25519 signature is read from a file next to it, with the extension
+// ".sig", as written by the sign subcommand.
+
+// signatureExt is the extension of the files holding the signatures of archives.
+const signatureExt = ".sig"
+
+// ParsePublicKey parses a hex-encoded Ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key %q: expected %d hex-encoded bytes", s, ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+// LoadPrivateKey reads an Ed25519 private key from a file holding its
+// hex-encoded seed.
+func LoadPrivateKey(file string) (ed25519.PrivateKey, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hex.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("%s: invalid private key: expected %d hex-encoded bytes", file, ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// SignFile writes the signature of the file at path with key to the file
+// with the same path and the extension ".sig".
+func SignFile(key ed25519.PrivateKey, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	sig := ed25519.Sign(key, data)
+	return WriteFile(path+signatureExt, func(w io.Writer) error {
+		_, err := w.Write(sig)
+		return err
+	})
+}
+
+// runSign runs the sign subcommand.
+func runSign(args []string) error {
+	var keyFile string
+	var genKey bool
+	fs := flag.NewFlagSet("bindata sign", flag.ContinueOnError)
+	fs.StringVar(&keyFile, "key", "", "file holding the private key (hex-encoded seed)")
+	fs.BoolVar(&genKey, "genkey", false, "generate a new private key into the -key file and print its public key")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if keyFile == "" {
+		return fmt.Errorf("sign: missing -key")
+	}
+	if genKey {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // never overwrite a key
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(f, hex.EncodeToString(priv.Seed())); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Println(hex.EncodeToString(pub))
+		return nil
+	}
+	key, err := LoadPrivateKey(keyFile)
+	if err != nil {
+		return err
+	}
+	for _, path := range fs.Args() {
+		if err := SignFile(key, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
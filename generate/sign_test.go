@@ -0,0 +1,88 @@
+package generate
+
+import (
+	"archive/zip"
+	"crypto/ed25519"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSignedOverride tests that only signed archives override the files
+// with -override-key.
+func TestSignedOverride(t *testing.T) {
+	dir := t.TempDir()
+	seed := make([]byte, ed25519.SeedSize)
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte(hex.EncodeToString(seed)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	key, err := LoadPrivateKey(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := hex.EncodeToString(key.Public().(ed25519.PublicKey))
+
+	archive := func(name string) string {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zw := zip.NewWriter(f)
+		w, err := zw.Create("play/bytes/11")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("patched"))
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	signed, unsigned, tampered := archive("signed.zip"), archive("unsigned.zip"), archive("tampered.zip")
+	for _, path := range []string{signed, tampered} {
+		if err := runSign([]string{"-key", keyFile, path}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(tampered, []byte("PK"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	data, ok := bindataData("play/bytes/11")
+	fmt.Printf("%s %v\n", data, ok)
+}
+`
+	tests := map[string]string{
+		signed:   "patched true\n",
+		unsigned: "10+1 bytes! true\n",
+		tampered: "10+1 bytes! true\n",
+		dir:      "10+1 bytes! true\n",
+	}
+	for env, want := range tests {
+		t.Setenv("BINDATA_BUNDLE", env)
+		if out := runGenerated(t, main, "-override", "BINDATA_BUNDLE", "-override-key", pub, "-r", testdata, filepath.Join(testdata, "play", "bytes")); out != want {
+			t.Errorf("%s: unexpected output:\n%s", filepath.Base(env), out)
+		}
+	}
+
+	if err := runGenerate([]string{"-override-key", pub, testdata}); err == nil {
+		t.Error("expected error without -override")
+	}
+	if err := runGenerate([]string{"-override", "X", "-override-key", pub[2:], testdata}); err == nil {
+		t.Error("expected error for invalid key")
+	}
+	if err := runSign([]string{"-genkey", "-key", keyFile}); err == nil {
+		t.Error("expected error for existing key")
+	}
+}
@@ -0,0 +1,198 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A Source is an input file, which does not need to be on disk.
+// It allows embedding generated content, archive entries or
+// data fetched over the network (see Generate).
+type Source interface {
+	Name() string                 // key of the file in the map
+	Open() (io.ReadCloser, error) // contents of the file
+}
+
+// fileSource is a file on disk.
+type fileSource struct {
+	name, path string
+}
+
+func (s fileSource) Name() string                 { return s.name }
+func (s fileSource) Open() (io.ReadCloser, error) { return os.Open(s.path) }
+
+// FileSource returns a source reading the file at path, stored under name.
+func FileSource(name, path string) Source {
+	return fileSource{filepath.FromSlash(name), path}
+}
+
+// readerSource is a source reading from an io.Reader,
+// which can only be opened once.
+type readerSource struct {
+	name string
+	r    io.Reader
+}
+
+func (s readerSource) Name() string                 { return s.name }
+func (s readerSource) Open() (io.ReadCloser, error) { return io.NopCloser(s.r), nil }
+
+// ReaderSource returns a source reading its contents from r, stored under name.
+// The source can only be opened once.
+func ReaderSource(name string, r io.Reader) Source {
+	return readerSource{filepath.FromSlash(name), r}
+}
+
+// BytesSource returns a source with the given contents, stored under name.
+func BytesSource(name string, data []byte) Source {
+	return readerSource{filepath.FromSlash(name), bytes.NewReader(data)}
+}
+
+// fsSource is a file of an fs.FS.
+type fsSource struct {
+	name, path string
+	fsys       fs.FS
+}
+
+func (s fsSource) Name() string                 { return s.name }
+func (s fsSource) Open() (io.ReadCloser, error) { return s.fsys.Open(s.path) }
+
+// addSource adds a file to the assets, applying the transforms, then the
+// normalization of text files.
+func (gen *generator) addSource(src Source) error {
+	name, path := src.Name(), ""
+	if !gen.only.Match(filepath.ToSlash(name)) {
+		return nil
+	}
+	if f, ok := src.(fileSource); ok {
+		path = f.path
+	}
+	if prev, ok := gen.assets[name]; ok {
+		if path != "" && filepath.Clean(path) == filepath.Clean(prev.Path) {
+			return nil // same file listed twice
+		}
+		msg := fmt.Sprintf("%s and %s both map to key %q", origin(prev.Path, name), origin(path, name), name)
+		switch gen.onCollision {
+		case "first":
+			gen.log.log(Event{Level: "warning", Kind: "collision", Message: msg + ", keeping the first", Key: name, Path: path})
+			return nil
+		case "last":
+			gen.log.log(Event{Level: "warning", Kind: "collision", Message: msg + ", keeping the last", Key: name, Path: path})
+		default:
+			return errors.New(msg)
+		}
+	}
+
+	if err := gen.ctx.Err(); err != nil {
+		return err
+	}
+	if u, ok := src.(urlSource); ok && u.ctx == nil {
+		u.ctx = gen.ctx // downloaded within the generation
+		src = u
+	}
+	if a, ok := gen.lazyAsset(src); ok {
+		gen.assets[name] = a
+		if gen.onProgress != nil {
+			gen.onProgress(Progress{Key: name, Size: a.size, Files: len(gen.assets)})
+		}
+		return nil
+	}
+	start := time.Now()
+	data, err := gen.read(src)
+	if err != nil {
+		return err
+	}
+	read := time.Since(start)
+	if data, err = gen.transforms.ApplyCached(gen.transformCache, filepath.ToSlash(name), data); err != nil {
+		return err
+	}
+	data = NormalizeText(data, gen.eol, gen.stripBOM)
+	gen.memUsed += Size(len(data))
+	gen.assets[name] = &Asset{Name: name, Path: path, Data: data, Mode: sourceMode(src, data), Time: sourceModTime(src)}
+	if gen.onProgress != nil {
+		gen.onProgress(Progress{Key: name, Size: len(data), Files: len(gen.assets), Read: read})
+	}
+	return nil
+}
+
+// origin describes where a file comes from for error messages.
+func origin(path, name string) string {
+	if path == "" {
+		return fmt.Sprintf("source %q", name)
+	}
+	return fmt.Sprintf("file %q", path)
+}
+
+// AddFS adds the files of fsys under root to the assets recursively.
+// The keys are the paths of the files relative to root.
+func (gen *generator) AddFS(fsys fs.FS, root string) error {
+	return fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		key := name
+		if name == root {
+			key = path.Base(name)
+		} else if root != "." {
+			key = strings.TrimPrefix(name, root+"/")
+		}
+		return gen.addSource(fsSource{key, name, fsys})
+	})
+}
+
+// read returns the contents of a source. The files unchanged since they were
+// read by a previous generation sharing the cache of gen, if any, are not
+// read again.
+func (gen *generator) read(src Source) ([]byte, error) {
+	f, ok := src.(fileSource)
+	var fi fs.FileInfo
+	if ok && gen.cache != nil {
+		var err error
+		if fi, err = os.Stat(f.path); err != nil {
+			return nil, err
+		}
+		if data, ok := gen.cache.get(f.path, fi); ok {
+			return data, nil
+		}
+	}
+	r, err := src.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(ctxReader{gen.ctx, r})
+	if err == nil && fi != nil {
+		gen.cache.put(f.path, fi, data)
+	}
+	return data, err
+}
+
+// lazyAsset returns the asset of a file source if its contents would exceed
+// the memory budget, in which case they are streamed to the output file
+// instead of being read. The contents of the files to transform or normalize
+// are always read, with a warning.
+func (gen *generator) lazyAsset(src Source) (*Asset, bool) {
+	f, ok := src.(fileSource)
+	if !ok || gen.maxMem == 0 {
+		return nil, false
+	}
+	fi, err := os.Stat(f.path)
+	if err != nil || !fi.Mode().IsRegular() || gen.memUsed+Size(fi.Size()) <= gen.maxMem {
+		return nil, false
+	}
+	name := filepath.ToSlash(f.name)
+	if gen.eol != "" || gen.stripBOM || slices.ContainsFunc(gen.transforms, func(t Transform) bool { return t.Match(name) }) {
+		msg := fmt.Sprintf("%s exceeds -max-mem but is read in memory to be transformed or normalized", f.path)
+		gen.log.log(Event{Level: "warning", Kind: "max-mem", Message: msg, Key: f.name, Path: f.path})
+		return nil, false
+	}
+	return &Asset{Name: f.name, Path: f.path, Mode: sourceMode(src, nil), Time: fi.ModTime(), Lazy: true, size: int(fi.Size())}, true
+}
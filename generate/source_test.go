@@ -0,0 +1,175 @@
+package generate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestSources tests adding files from sources other than the file system.
+func TestSources(t *testing.T) {
+	gen := newGenerator(context.Background(), nil)
+
+	fsys := fstest.MapFS{
+		"dist/index.html":  {Data: []byte("<html>")},
+		"dist/js/app.js":   {Data: []byte("app()")},
+		"other/ignored.js": {Data: []byte("x")},
+	}
+	if err := gen.AddFS(fsys, "dist"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.addSource(ReaderSource("gen/reader.txt", strings.NewReader("reader"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.addSource(BytesSource("gen/bytes.txt", []byte("bytes"))); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"index.html":     "<html>",
+		"js/app.js":      "app()",
+		"gen/reader.txt": "reader",
+		"gen/bytes.txt":  "bytes",
+	}
+	if len(gen.assets) != len(want) {
+		t.Errorf("expected %d assets, got %d", len(want), len(gen.assets))
+	}
+	for name, data := range want {
+		if a, ok := gen.assets[name]; !ok || string(a.Data) != data {
+			t.Errorf("%s: expected %q, got %+v", name, data, a)
+		}
+	}
+}
+
+// TestGenerateSources tests generations embedding sources along with the
+// inputs of the command line.
+func TestGenerateSources(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "gen.go")
+	args := []string{"-o", out, "-r", testdata, filepath.Join(testdata, "play", "bytes")}
+	err := Generate(context.Background(), args, nil,
+		FileSource("gif/gopher.gif", filepath.Join(testdata, "gopher.gif")),
+		ReaderSource("gen/reader.txt", strings.NewReader("reader")),
+		BytesSource("gen/bytes.txt", []byte("bytes")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := ParseGenerated(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"gen/bytes.txt", "gen/reader.txt", "gif/gopher.gif", "play/bytes/11", "play/bytes/12", "play/bytes/13"}
+	if keys := g.Keys(); !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected keys %v, got %v", want, keys)
+	}
+	gif, err := os.ReadFile(filepath.Join(testdata, "gopher.gif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, data := range map[string]string{"gen/reader.txt": "reader", "gen/bytes.txt": "bytes", "gif/gopher.gif": string(gif)} {
+		if got, err := g.Read(key); err != nil || string(got) != data {
+			t.Errorf("%s: expected %d bytes, got %d (%v)", key, len(data), len(got), err)
+		}
+	}
+
+	// the sources belong to no directory
+	err = Generate(context.Background(), []string{"-per-dir-output", filepath.Join(testdata, "play")}, nil, BytesSource("a", nil))
+	if err == nil || err.Error() != "sources cannot be combined with -per-dir-output" {
+		t.Errorf("expected a -per-dir-output error, got %v", err)
+	}
+}
+
+// TestCollisions tests the policies applied to files with the same key.
+func TestCollisions(t *testing.T) {
+	gen := newGenerator(context.Background(), nil)
+
+	for policy, want := range map[string]string{"first": "1", "last": "2", "error": ""} {
+		gen.assets, gen.onCollision = make(map[string]*Asset), policy
+		if err := gen.addSource(readerSource{"a", strings.NewReader("1")}); err != nil {
+			t.Fatal(err)
+		}
+		err := gen.addSource(readerSource{"a", strings.NewReader("2")})
+		if policy == "error" {
+			if err == nil || err.Error() != `source "a" and source "a" both map to key "a"` {
+				t.Errorf("unexpected error %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(gen.assets["a"].Data); got != want {
+			t.Errorf("%s: expected %q, got %q", policy, want, got)
+		}
+	}
+
+	// the same file listed twice is not a collision
+	gen.assets = make(map[string]*Asset)
+	path := filepath.Join(testdata, "empty")
+	for i := 0; i < 2; i++ {
+		if err := gen.addSource(fileSource{"empty", path}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestVirtualPrefix tests the embedding of inputs under virtual prefixes.
+func TestVirtualPrefix(t *testing.T) {
+	if path, virtual, ok := SplitInput("dist/:static/"); !ok || path != "dist/" || virtual != "static/" {
+		t.Errorf("unexpected split %q %q %v", path, virtual, ok)
+	}
+	if _, _, ok := SplitInput(filepath.Join(testdata, "empty")); ok {
+		t.Error("unexpected split of a path without prefix")
+	}
+
+	gen := newGenerator(context.Background(), nil)
+	if err := gen.AddPathAs(filepath.Join(testdata, "play", "bytes"), "static/b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.AddPathAs(filepath.Join(testdata, "empty"), ""); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for key := range gen.assets {
+		keys = append(keys, filepath.ToSlash(key))
+	}
+	sort.Strings(keys)
+	if want := []string{"empty", "static/b/11", "static/b/12", "static/b/13"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected %v, got %v", want, keys)
+	}
+}
+
+// TestLayers tests that the files of later layers override the earlier ones.
+func TestLayers(t *testing.T) {
+	base, overrides := t.TempDir(), t.TempDir()
+	for dir, files := range map[string][]string{base: {"logo.png", "css/theme.css"}, overrides: {"css/theme.css", "extra.txt"}} {
+		for _, name := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(dir), 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	gen := newGenerator(context.Background(), nil)
+	out := filepath.Join(t.TempDir(), "out.go")
+	if err := gen.run([]string{"-o", out, "-layer", base, "-layer", overrides}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"logo.png": base, "css/theme.css": overrides, "extra.txt": overrides}
+	if len(gen.assets) != len(want) {
+		t.Errorf("unexpected assets %v", gen.assets)
+	}
+	for key, dir := range want {
+		if a, ok := gen.assets[filepath.FromSlash(key)]; !ok || string(a.Data) != dir {
+			t.Errorf("%s: expected the file of %s, got %+v", key, dir, a)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// With -max-mem, the contents of the files beyond the memory budget are not
+// read during the generation but streamed from their source files to the
+// output file, and the output file itself is streamed instead of rendered
+// in memory. The templates print a marker in place of the contents of each
+// file, which a spliceWriter replaces by the contents as it writes the output.
+
+// streamMarker starts the markers of the contents of the files streamed.
+const streamMarker = "\x00bindata:stream:"
+
+// A streamRef is the index of a file streamed to the output file. It is
+// printed by the templates as a marker replaced by the contents of the file.
+type streamRef int
+
+// Format prints the marker of the file.
+func (r streamRef) Format(s fmt.State, c rune) {
+	fmt.Fprintf(s, "%s%d\x00", streamMarker, int(r))
+}
+
+// A spliceWriter writes the output of a template to w, replacing the markers
+// of the files by their contents formatted by the formatters of files.
+// The markers are written by the templates in a single write.
+type spliceWriter struct {
+	w     io.Writer
+	files []io.WriterTo
+}
+
+// Write writes p to the underlying writer, or the contents of a file if p is
+// its marker.
+func (s *spliceWriter) Write(p []byte) (int, error) {
+	if rest, ok := bytes.CutPrefix(p, []byte(streamMarker)); ok && bytes.HasSuffix(rest, []byte{0}) {
+		if i, err := strconv.Atoi(string(rest[:len(rest)-1])); err == nil && i >= 0 && i < len(s.files) {
+			if _, err := s.files[i].WriteTo(s.w); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+	return s.w.Write(p)
+}
+
+// A lazyFile reads a file opened on the first read and closed at its end,
+// so that the files streamed are not all open at once.
+type lazyFile struct {
+	path string
+	f    *os.File
+}
+
+// Read reads from the file, opening it if needed.
+func (r *lazyFile) Read(p []byte) (int, error) {
+	if r.f == nil {
+		f, err := os.Open(r.path)
+		if err != nil {
+			return 0, err
+		}
+		r.f = f
+	}
+	n, err := r.f.Read(p)
+	if err != nil {
+		r.f.Close()
+	}
+	return n, err
+}
+
+// A streamFormatter formats the contents of an asset for the storage type of
+// the map each time it is written, reading them from the source file if they
+// are not held in memory.
+type streamFormatter struct {
+	a      *Asset
+	format func(r io.Reader) formatter // formatter for the storage type of the map
+}
+
+// WriteTo writes the contents of the asset formatted to w.
+func (f streamFormatter) WriteTo(w io.Writer) (int64, error) {
+	var r io.Reader = bytes.NewReader(f.a.Data)
+	if f.a.Lazy {
+		r = &lazyFile{path: f.a.Path}
+	}
+	return f.format(r).WriteTo(w)
+}
@@ -0,0 +1,71 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMaxMem tests that the files streamed with -max-mem are generated as
+// if they were read in memory.
+func TestMaxMem(t *testing.T) {
+	dir := t.TempDir()
+	for _, flags := range [][]string{nil, {"-s"}, {"-layout", "slice"}, {"-checksum", "-o-copy", filepath.Join(dir, "copy.go")}} {
+		args := append(flags, "-r", testdata, filepath.Join(testdata, "play", "bytes"), filepath.Join(testdata, "empty"))
+		want, got := filepath.Join(dir, "want.go"), filepath.Join(dir, "got.go")
+		if err := runGenerate(append([]string{"-o", want}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		gen := newGenerator(context.Background(), nil)
+		if err := gen.run(append([]string{"-o", got, "-max-mem", "20"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		lazy := 0
+		for _, a := range gen.assets {
+			if a.Lazy {
+				lazy++
+			}
+		}
+		if lazy != 2 {
+			t.Errorf("%v: expected 2 files streamed, got %d", flags, lazy)
+		}
+		a, err := os.ReadFile(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%v: unexpected output:\n%s", flags, b)
+		}
+		if len(flags) > 2 {
+			if c, err := os.ReadFile(filepath.Join(dir, "copy.go")); err != nil || !bytes.Equal(b, c) {
+				t.Errorf("copy differs from the output file: %v", err)
+			}
+		}
+	}
+	if err := runGenerate([]string{"-max-mem", "1MB", "-compress", "gzip", testdata}); err == nil {
+		t.Error("expected error with -compress")
+	}
+
+	// the files to normalize are read, with a warning
+	var buf bytes.Buffer
+	gen := newGenerator(context.Background(), nil)
+	gen.log.w = &buf
+	if err := gen.run([]string{"-o", filepath.Join(dir, "eol.go"), "-max-mem", "20", "-normalize-eol", "lf", "-r", testdata, filepath.Join(testdata, "play", "bytes")}); err != nil {
+		t.Fatal(err)
+	}
+	for key, a := range gen.assets {
+		if a.Lazy {
+			t.Errorf("%s streamed despite -normalize-eol", key)
+		}
+	}
+	if !strings.Contains(buf.String(), "exceeds -max-mem") {
+		t.Errorf("expected a warning, got %q", buf.String())
+	}
+}
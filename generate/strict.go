@@ -0,0 +1,33 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// InputErrors is the list of the inputs that could not be read.
+type InputErrors []error
+
+// Error returns a summary followed by the errors, one per line.
+func (errs InputErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d input(s) could not be read:", len(errs))
+	for _, err := range errs {
+		b.WriteString("\n\t")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// inputError collects err in strict mode if it is an error reading an input.
+// It returns the errors to stop at.
+func (gen *generator) inputError(err error) error {
+	var pathErr *fs.PathError
+	if !gen.strict || !errors.As(err, &pathErr) {
+		return err
+	}
+	gen.inputErrors = append(gen.inputErrors, err)
+	return nil
+}
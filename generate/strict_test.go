@@ -0,0 +1,29 @@
+package generate
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestStrict tests the aggregation of input errors.
+func TestStrict(t *testing.T) {
+	missing := []string{filepath.Join(testdata, "missing1"), filepath.Join(testdata, "missing2")}
+	args := append([]string{"-r", testdata, filepath.Join(testdata, "play")}, missing...)
+
+	err := runGenerate(args)
+	if err == nil || !strings.Contains(err.Error(), "missing1") || strings.Contains(err.Error(), "missing2") {
+		t.Errorf("expected error for the first missing input, got %v", err)
+	}
+
+	err = runGenerate(append([]string{"-strict"}, args...))
+	var errs InputErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 input errors, got %v", err)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "2 input(s) could not be read:") ||
+		!strings.Contains(msg, "missing1") || !strings.Contains(msg, "missing2") {
+		t.Errorf("unexpected report %q", msg)
+	}
+}
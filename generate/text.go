@@ -0,0 +1,35 @@
+package generate
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// utf8BOM is the byte order mark starting some UTF-8 text files.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// IsText reports whether data looks like text: valid UTF-8 without NUL bytes.
+func IsText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
+
+// NormalizeText returns data with its line endings converted to eol (lf or
+// crlf, empty to keep them) and without its UTF-8 byte order mark if stripBOM
+// is set, so that files checked out on different systems are embedded alike.
+// Binary data is returned as is.
+func NormalizeText(data []byte, eol string, stripBOM bool) []byte {
+	if eol == "" && !stripBOM || !IsText(data) {
+		return data
+	}
+	if stripBOM {
+		data = bytes.TrimPrefix(data, utf8BOM)
+	}
+	switch eol {
+	case "lf":
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	case "crlf":
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+	return data
+}
@@ -0,0 +1,49 @@
+package generate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestNormalizeText tests the normalization of the line endings and byte order marks of text files.
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in, eol  string
+		stripBOM bool
+		want     string
+	}{
+		{"a\r\nb\nc\r\n", "", false, "a\r\nb\nc\r\n"},
+		{"a\r\nb\nc\r\n", "lf", false, "a\nb\nc\n"},
+		{"a\r\nb\nc\r\n", "crlf", false, "a\r\nb\r\nc\r\n"},
+		{"\xef\xbb\xbfa\r\n", "", true, "a\r\n"},
+		{"\xef\xbb\xbfa\r\n", "lf", true, "a\n"},
+		{"\xef\xbb\xbfa\r\n", "lf", false, "\xef\xbb\xbfa\n"},
+		{"GIF\x00\r\n", "lf", true, "GIF\x00\r\n"},                   // NUL byte
+		{"\xef\xbb\xbf\xff\r\n", "lf", true, "\xef\xbb\xbf\xff\r\n"}, // invalid UTF-8
+	}
+	for _, test := range tests {
+		if got := string(NormalizeText([]byte(test.in), test.eol, test.stripBOM)); got != test.want {
+			t.Errorf("%q (%s, %v): expected %q, got %q", test.in, test.eol, test.stripBOM, test.want, got)
+		}
+	}
+}
+
+// TestNormalizeFlags tests the normalization of the files of a generation.
+func TestNormalizeFlags(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("\xef\xbb\xbfa\r\nb\r\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	gen := newGenerator(context.Background(), nil)
+	if err := gen.run([]string{"-normalize-eol=lf", "-strip-bom", "-o", filepath.Join(dir, "out.go"), "-r", dir, filepath.Join(dir, "a.txt")}); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(gen.assets["a.txt"].Data); got != "a\nb\n" {
+		t.Errorf("expected %q, got %q", "a\nb\n", got)
+	}
+	if err := runGenerate([]string{"-normalize-eol=cr", dir}); err == nil {
+		t.Error("expected error for invalid line ending")
+	}
+}
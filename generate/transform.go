@@ -0,0 +1,128 @@
+package generate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// A Transform pipes the contents of the assets matching a glob pattern
+// through an external command before they are embedded.
+type Transform struct {
+	Pattern string   // glob pattern scoping the transform (empty matches all)
+	Command []string // command and its arguments
+}
+
+// Match reports whether the transform applies to the asset with the given key,
+// with the syntax of the other patterns (see matchPattern).
+func (t Transform) Match(name string) bool {
+	return t.Pattern == "" || matchPattern(t.Pattern, name)
+}
+
+// Apply runs the command with data on its standard input
+// and returns what it writes on its standard output.
+func (t Transform) Apply(data []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(t.Command[0], t.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %v: %s", t.Command[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s: %v", t.Command[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// applyCached applies the transform, reusing its output from the cache
+// directory dir if it already ran on the same data, or storing it there.
+// The entries are keyed by a hash of the command and the data.
+func (t Transform) applyCached(dir string, data []byte) ([]byte, error) {
+	if dir == "" {
+		return t.Apply(data)
+	}
+	h := sha256.New()
+	for _, arg := range t.Command {
+		h.Write([]byte(arg))
+		h.Write([]byte{0})
+	}
+	h.Write(data)
+	file := filepath.Join(dir, hex.EncodeToString(h.Sum(nil)))
+	if out, err := os.ReadFile(file); err == nil {
+		return out, nil
+	}
+	out, err := t.Apply(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return out, WriteFile(file, func(w io.Writer) error {
+		_, err := w.Write(out)
+		return err
+	})
+}
+
+// Transforms is a list of transforms usable as a repeatable command line flag.
+type Transforms []Transform
+
+// String returns the transforms as they would appear on the command line.
+func (ts *Transforms) String() string {
+	var s []string
+	for _, t := range *ts {
+		cmd := strings.Join(t.Command, " ")
+		if t.Pattern != "" {
+			cmd = t.Pattern + "=" + cmd
+		}
+		s = append(s, cmd)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Set parses a transform of the form "[pattern=]command args" and adds it to the list.
+// The pattern is only recognized if the '=' occurs before the first space.
+func (ts *Transforms) Set(value string) error {
+	var t Transform
+	if i := strings.Index(value, "="); i >= 0 && !strings.ContainsAny(value[:i], " \t") {
+		t.Pattern, value = value[:i], value[i+1:]
+		if _, err := path.Match(strings.ReplaceAll(t.Pattern, "**", "*"), ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", t.Pattern, err)
+		}
+	}
+	t.Command = strings.Fields(value)
+	if len(t.Command) == 0 {
+		return fmt.Errorf("missing command")
+	}
+	*ts = append(*ts, t)
+	return nil
+}
+
+// Apply pipes data through all the transforms matching name, in order.
+func (ts Transforms) Apply(name string, data []byte) ([]byte, error) {
+	return ts.ApplyCached("", name, data)
+}
+
+// ApplyCached is like Apply, caching the outputs of the transforms in the
+// directory dir if it is not empty.
+func (ts Transforms) ApplyCached(dir, name string, data []byte) ([]byte, error) {
+	for _, t := range ts {
+		if !t.Match(name) {
+			continue
+		}
+		var err error
+		if data, err = t.applyCached(dir, data); err != nil {
+			return nil, fmt.Errorf("transform %s: %v", name, err)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,88 @@
+package generate
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTransforms tests the parsing and scoping of transforms.
+func TestTransforms(t *testing.T) {
+	var ts Transforms
+	for _, v := range []string{"*.txt=tr a-z A-Z", "sub/*=tr -d x", "tr = -"} {
+		if err := ts.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := ts.String(); got != "*.txt=tr a-z A-Z, sub/*=tr -d x, tr = -" {
+		t.Fatalf("unexpected string %q", got)
+	}
+
+	tests := []struct {
+		name, in, out string
+	}{
+		{"a/b.txt", "abc=", "ABC-"},
+		{"sub/b.txt", "xyz=", "XYZ-"},
+		{"sub/b.bin", "xyz=", "yz-"},
+		{"b.bin", "x=y", "x-y"},
+	}
+	for _, test := range tests {
+		out, err := ts.Apply(test.name, []byte(test.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != test.out {
+			t.Errorf("%s: expected %q, got %q", test.name, test.out, out)
+		}
+	}
+
+	if err := ts.Set("*.txt="); err == nil {
+		t.Error("expected error for missing command")
+	}
+	if err := ts.Set("[=cat"); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+	if _, err := (Transforms{{Command: []string{"false"}}}).Apply("x", nil); err == nil {
+		t.Error("expected error for failing command")
+	}
+
+	// ** matches any number of directories, as in the other patterns
+	tr := Transform{Pattern: "static/**/*.js"}
+	for name, want := range map[string]bool{"static/app.js": true, "static/js/vendor/lib.js": true, "lib/app.js": false} {
+		if got := tr.Match(name); got != want {
+			t.Errorf("%s: expected match %v, got %v", name, want, got)
+		}
+	}
+}
+
+// TestTransformCache tests that transforms do not run again on unchanged data.
+func TestTransformCache(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip(err)
+	}
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	tr := Transform{Command: []string{"sh", "-c", "echo >> " + runs + "; tr a-z A-Z"}}
+	cache := filepath.Join(dir, "cache")
+	for _, in := range []string{"abc", "abc", "def"} {
+		out, err := tr.applyCached(cache, []byte(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != strings.ToUpper(in) {
+			t.Errorf("expected %s, got %s", strings.ToUpper(in), out)
+		}
+	}
+	if b, _ := os.ReadFile(runs); len(b) != 2 {
+		t.Errorf("expected 2 runs, got %d", len(b))
+	}
+	tr.Command[2] = "tr a-z A-Z" // another command misses the cache
+	if out, err := tr.applyCached(cache, []byte("abc")); err != nil || string(out) != "ABC" {
+		t.Errorf("unexpected output %q (%v)", out, err)
+	}
+	if entries, _ := os.ReadDir(cache); len(entries) != 3 {
+		t.Errorf("expected 3 cache entries, got %d", len(entries))
+	}
+}
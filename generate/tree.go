@@ -0,0 +1,173 @@
+package generate
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// A Tree is a hierarchy of types giving access to the files by path with
+// Go identifiers, e.g. Assets.Templates.IndexHTML() for "templates/index.html".
+type Tree struct {
+	Name string     // name of the variable at the root of the tree
+	Dirs []*treeDir // directories, the root first
+}
+
+// A treeDir is a directory of a tree, declared as a struct type with a field
+// per subdirectory and a method per file.
+type treeDir struct {
+	Path    string
+	Type    string
+	Subdirs []treeEntry
+	Files   []treeEntry
+	Width   int // width of the names of the subdirectories, to align them
+}
+
+// A treeEntry is a subdirectory (with its type) or a file (with its key) of a directory.
+type treeEntry struct {
+	Name string
+	Type string // type of a subdirectory
+	Key  string // key of a file
+}
+
+// namings are the strategies converting path elements to identifiers:
+// "initialisms" keeps the common initialisms in upper case (IndexHTML)
+// and "plain" only capitalizes words (IndexHtml).
+var namings = map[string]func(string) string{
+	"initialisms": identifier,
+	"plain": func(name string) string {
+		words := strings.FieldsFunc(name, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for i, w := range words {
+			r := []rune(w)
+			words[i] = strings.ToUpper(string(r[0])) + string(r[1:])
+		}
+		return strings.Join(words, "")
+	},
+}
+
+// NewTree returns the tree of the files with the given slash separated keys,
+// rooted at a variable of the given name, naming the path elements with the
+// given strategy. Elements starting with a digit are prefixed with "X".
+// It fails if two elements of a directory map to the same identifier.
+func NewTree(name string, keys []string, naming string) (*Tree, error) {
+	ident, err := elementIdent(naming)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tree{Name: name}
+	dirs := make(map[string]*treeDir)
+	seen := make(map[string]map[string]string) // elements by identifier per directory
+	var dir func(p string) (*treeDir, error)
+	dir = func(p string) (*treeDir, error) {
+		if d, ok := dirs[p]; ok {
+			return d, nil
+		}
+		d := &treeDir{Path: p, Type: unexported(name) + "Tree"}
+		if p != "." {
+			parent, err := dir(path.Dir(p))
+			if err != nil {
+				return nil, err
+			}
+			id, err := ident(path.Base(p))
+			if err != nil {
+				return nil, err
+			}
+			if err := claim(seen, parent.Path, id, p); err != nil {
+				return nil, err
+			}
+			d.Type = parent.Type + "_" + id
+			parent.Subdirs = append(parent.Subdirs, treeEntry{Name: id, Type: d.Type})
+			parent.Width = max(parent.Width, len(id))
+		}
+		dirs[p] = d
+		t.Dirs = append(t.Dirs, d)
+		return d, nil
+	}
+
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	if _, err := dir("."); err != nil {
+		return nil, err
+	}
+	for _, key := range sorted {
+		d, err := dir(path.Dir(key))
+		if err != nil {
+			return nil, err
+		}
+		id, err := ident(path.Base(key))
+		if err != nil {
+			return nil, err
+		}
+		if err := claim(seen, d.Path, id, key); err != nil {
+			return nil, err
+		}
+		d.Files = append(d.Files, treeEntry{Name: id, Key: key})
+	}
+	return t, nil
+}
+
+// ReceiverMethods returns the methods of the type giving access to the files
+// with the given slash separated keys (-receiver), named after their whole
+// paths with the given strategy, e.g. StaticIndexHTML() for
+// "static/index.html", sorted by key. Names starting with a digit are
+// prefixed with "X". It fails if two keys map to the same identifier.
+func ReceiverMethods(keys []string, naming string) ([]treeEntry, error) {
+	ident, err := elementIdent(naming)
+	if err != nil {
+		return nil, err
+	}
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	methods := make([]treeEntry, 0, len(sorted))
+	seen := make(map[string]map[string]string)
+	for _, key := range sorted {
+		id, err := ident(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := claim(seen, ".", id, key); err != nil {
+			return nil, err
+		}
+		methods = append(methods, treeEntry{Name: id, Key: key})
+	}
+	return methods, nil
+}
+
+// elementIdent returns the function converting path elements to exported
+// identifiers with the given naming strategy, prefixing with "X" those
+// which would start with a digit.
+func elementIdent(naming string) (func(elem string) (string, error), error) {
+	convert, ok := namings[naming]
+	if !ok {
+		return nil, fmt.Errorf("invalid naming strategy %q", naming)
+	}
+	return func(elem string) (string, error) {
+		id := convert(elem)
+		if id != "" && unicode.IsDigit([]rune(id)[0]) {
+			id = "X" + id
+		}
+		if !token.IsIdentifier(id) || !token.IsExported(id) {
+			return "", fmt.Errorf("cannot derive an identifier for %q", elem)
+		}
+		return id, nil
+	}, nil
+}
+
+// claim records that the path p maps to the identifier id in directory dir,
+// failing if another path of the directory already maps to it.
+func claim(seen map[string]map[string]string, dir, id, p string) error {
+	if seen[dir] == nil {
+		seen[dir] = make(map[string]string)
+	}
+	if other, ok := seen[dir][id]; ok {
+		return fmt.Errorf("%q and %q both map to %s", other, p, id)
+	}
+	seen[dir][id] = p
+	return nil
+}
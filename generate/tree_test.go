@@ -0,0 +1,102 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestNewTree tests the identifiers of the tree of accessors.
+func TestNewTree(t *testing.T) {
+	tree, err := NewTree("Assets", []string{"templates/index.html", "404.html", "templates/partials/nav.tmpl"}, "initialisms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range tree.Dirs {
+		for _, s := range d.Subdirs {
+			got = append(got, d.Type+"."+s.Name+" "+s.Type)
+		}
+		for _, f := range d.Files {
+			got = append(got, d.Type+"."+f.Name+"() "+f.Key)
+		}
+	}
+	want := []string{
+		"assetsTree.Templates assetsTree_Templates",
+		"assetsTree.X404HTML() 404.html",
+		"assetsTree_Templates.Partials assetsTree_Templates_Partials",
+		"assetsTree_Templates.IndexHTML() templates/index.html",
+		"assetsTree_Templates_Partials.NavTmpl() templates/partials/nav.tmpl",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %q, got %q", want[i], got[i])
+		}
+	}
+
+	tree, err = NewTree("Assets", []string{"api.json"}, "plain")
+	if err != nil || tree.Dirs[0].Files[0].Name != "ApiJson" {
+		t.Errorf("plain naming: unexpected tree %+v (%v)", tree, err)
+	}
+	for _, keys := range [][]string{
+		{"a-b.txt", "a_b.txt"},
+		{"static/x", "static.go", "Static/y"},
+		{"_"},
+	} {
+		if _, err := NewTree("Assets", keys, "initialisms"); err == nil {
+			t.Errorf("%q: expected an error", keys)
+		}
+	}
+	if _, err := NewTree("Assets", nil, "snake"); err == nil {
+		t.Error("invalid naming: expected an error")
+	}
+}
+
+// TestTree tests the generated accessors.
+func TestTree(t *testing.T) {
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Printf("%s|%s\n", Assets.Play.Bytes.X11(), Assets.Play.HelloGo()[:12])
+}
+`
+	for _, flags := range [][]string{nil, {"-s"}, {"-layout", "blob"}} {
+		args := append(flags, "-tree", "Assets", "-r", testdata, filepath.Join(testdata, "play"))
+		if out := runGenerated(t, main, args...); out != "10+1 bytes!|package main\n" {
+			t.Errorf("%v: unexpected output:\n%s", flags, out)
+		}
+	}
+}
+
+// TestReceiver tests the methods of the receiver type.
+func TestReceiver(t *testing.T) {
+	methods, err := ReceiverMethods([]string{"templates/index.html", "404.html"}, "initialisms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(methods) != 2 || methods[0].Name != "X404HTML" || methods[1].Name != "TemplatesIndexHTML" || methods[1].Key != "templates/index.html" {
+		t.Errorf("unexpected methods %+v", methods)
+	}
+	if _, err := ReceiverMethods([]string{"a/b.txt", "a-b.txt"}, "initialisms"); err == nil {
+		t.Error("expected an error for identifiers in conflict")
+	}
+
+	const main = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Printf("%s|%s\n", Assets{}.PlayBytes11(), Assets{}.PlayHelloGo()[:12])
+}
+`
+	for _, flags := range [][]string{nil, {"-s"}, {"-layout", "slice"}} {
+		args := append(flags, "-receiver", "Assets", "-r", testdata, filepath.Join(testdata, "play"))
+		if out := runGenerated(t, main, args...); out != "10+1 bytes!|package main\n" {
+			t.Errorf("%v: unexpected output:\n%s", flags, out)
+		}
+	}
+}
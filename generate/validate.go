@@ -0,0 +1,161 @@
+package generate
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// A Problem is an issue found when validating a configuration and its inputs.
+type Problem struct {
+	Kind    string `json:"kind"`    // config, input, collision, group or budget
+	Subject string `json:"subject"` // file, input, key or pattern concerned
+	Message string `json:"message"`
+}
+
+// String returns the problem prefixed by its kind and subject.
+func (p Problem) String() string {
+	if p.Subject == "" {
+		return p.Kind + ": " + p.Message
+	}
+	return p.Kind + ": " + p.Subject + ": " + p.Message
+}
+
+// A Validation describes what to validate: the configuration file (if any)
+// and the inputs, with the options of a generation affecting their keys.
+type Validation struct {
+	Config      string   // configuration file
+	Budget      Size     // total budget overriding that of the configuration
+	Prefix      string   // root path for the keys (-r)
+	Abs         string   // policy for keys outside of the root (-abs)
+	FromArchive bool     // treat archives as directory trees (-from-archive)
+	Gitignore   bool     // honour .gitignore files in directories (-gitignore)
+	Paths       []string // inputs
+}
+
+// Validate reports the problems of a configuration and its inputs without
+// generating anything: unknown fields, unreadable inputs, files of different
+// inputs with the same key, invalid or unused group patterns, files belonging
+// to several groups and exceeded budgets. It reads the inputs, with the
+// ignore files in effect.
+func Validate(v Validation) []Problem {
+	var problems []Problem
+	report := func(kind, subject string, err error) {
+		problems = append(problems, Problem{kind, subject, err.Error()})
+	}
+
+	var config Config
+	if v.Config != "" {
+		c, err := LoadConfig(v.Config)
+		if err != nil {
+			report("config", v.Config, err)
+		} else {
+			config = *c
+		}
+	}
+	if v.Budget != 0 {
+		config.Budget = v.Budget
+	}
+
+	gen := newGenerator(context.Background(), nil)
+	if v.Gitignore {
+		gen.ignoreFiles = append(gen.ignoreFiles, ".gitignore")
+	}
+	gen.strict = true
+	all := make(map[string]*Asset)
+	from := make(map[string]string) // inputs of the keys
+	for _, input := range v.Paths {
+		gen.assets, gen.inputErrors = make(map[string]*Asset), nil
+		if err := gen.addInput(input, v.Prefix, v.Abs, v.FromArchive); err != nil {
+			report("input", input, err)
+		}
+		for _, err := range gen.inputErrors {
+			report("input", input, err)
+		}
+		for _, key := range slices.Sorted(maps.Keys(gen.assets)) {
+			if other, ok := from[key]; ok {
+				report("collision", filepath.ToSlash(key), fmt.Errorf("from both %s and %s", other, input))
+				continue
+			}
+			from[key], all[key] = input, gen.assets[key]
+		}
+	}
+
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, filepath.ToSlash(key))
+	}
+	groups := make(map[string][]string)
+	for _, tag := range slices.Sorted(maps.Keys(config.Groups)) {
+		for _, pattern := range config.Groups[tag] {
+			if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+				report("group", tag, fmt.Errorf("invalid pattern %q", pattern))
+				continue
+			}
+			groups[tag] = append(groups[tag], pattern)
+			if !slices.ContainsFunc(keys, func(key string) bool { return matchPattern(pattern, key) }) {
+				report("group", tag, fmt.Errorf("pattern %q matches no file", pattern))
+			}
+		}
+	}
+	if _, err := AssignGroups(all, groups); err != nil {
+		report("group", "", err)
+	}
+	if err := CheckBudgets(all, config.Budget, config.Budgets); err != nil {
+		report("budget", "", err)
+	}
+	return problems
+}
+
+// writeProblems writes the problems one per line, or as a JSON array.
+func writeProblems(w io.Writer, problems []Problem, asJSON bool) error {
+	if asJSON {
+		if problems == nil {
+			problems = []Problem{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(problems)
+	}
+	for _, p := range problems {
+		if _, err := fmt.Fprintln(w, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runValidate runs the validate subcommand.
+func runValidate(args []string) error {
+	var v Validation
+	var asJSON bool
+	fs := flag.NewFlagSet("bindata validate", flag.ContinueOnError)
+	fs.StringVar(&v.Config, "c", "", "configuration file")
+	fs.Var(&v.Budget, "budget", "maximum total size of the files (e.g. 10MB)")
+	fs.StringVar(&v.Prefix, "r", "", "root path for map keys")
+	fs.StringVar(&v.Abs, "abs", "reject", "policy for keys absolute or outside of the root: reject, trim or keep")
+	fs.BoolVar(&v.FromArchive, "from-archive", false, "treat zip and tar archives as directory trees")
+	fs.BoolVar(&v.Gitignore, "gitignore", false, "honour .gitignore files in directories")
+	fs.BoolVar(&asJSON, "json", false, "report the problems as a JSON array")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	v.Paths = fs.Args()
+
+	problems := Validate(v)
+	if err := writeProblems(os.Stdout, problems, asJSON); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("validate: %d problem(s) found", len(problems))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package generate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidate tests the problems reported for a configuration and its inputs.
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"a/x.txt":   "x",
+		"b/x.txt":   "xx",
+		"b/big.bin": strings.Repeat("0", 100),
+		"config.json": `{
+	"budget": "50B",
+	"groups": {"dev": ["*.bin", "b/**", "*.css", "[x"], "prod": ["big.bin"]},
+	"extra": true
+}`,
+		"valid.json": `{"groups": {"dev": ["*.bin"]}}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := filepath.Join(dir, "config.json")
+	a, b, missing := filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "missing")
+
+	problems := Validate(Validation{Config: config, Budget: 50, Paths: []string{a + ":", b + ":", missing + ":m"}})
+	var kinds []string
+	for _, p := range problems {
+		kinds = append(kinds, p.Kind)
+	}
+	if got, want := strings.Join(kinds, " "), "config collision input budget"; got != want {
+		t.Errorf("expected %s, got %s:\n%v", want, got, problems)
+	}
+
+	// the groups are checked once the configuration is valid
+	if err := os.WriteFile(config, []byte(`{"budget": "50B", "groups": {"dev": ["*.bin", "b/**", "*.css", "[x"], "prod": ["big.bin"]}}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	problems = Validate(Validation{Config: config, Prefix: dir, Paths: []string{a, b}})
+	var lines bytes.Buffer
+	if err := writeProblems(&lines, problems, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`group: dev: pattern "*.css" matches no file`,
+		`group: dev: invalid pattern "[x"`,
+		`group: file "b/big.bin" belongs to groups dev and prod`,
+		"budget: budget exceeded:",
+	} {
+		if !strings.Contains(lines.String(), want) {
+			t.Errorf("missing problem %q in:\n%s", want, lines.String())
+		}
+	}
+
+	if problems := Validate(Validation{Config: filepath.Join(dir, "valid.json"), Prefix: dir, Paths: []string{b}}); problems != nil {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+	var out bytes.Buffer
+	if err := writeProblems(&out, nil, true); err != nil || out.String() != "[]\n" {
+		t.Errorf("expected an empty JSON array, got %q (%v)", out.String(), err)
+	}
+}
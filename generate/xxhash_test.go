@@ -0,0 +1,28 @@
+package generate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestXXHash64(t *testing.T) {
+	for _, test := range []struct {
+		data string
+		want uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{strings.Repeat("0123456789", 5)[:47], 0x6d0c48a1bc492eba}, // stripes of 32 bytes
+	} {
+		if got := XXHash64([]byte(test.data)); got != test.want {
+			t.Errorf("XXHash64(%q) = %#x, want %#x", test.data, got, test.want)
+		}
+	}
+
+	// all the code paths: stripes, 8-byte words, 4-byte word and bytes
+	long := []byte(strings.Repeat("0123456789", 10)[:32+8+4+3])
+	if XXHash64(long) == XXHash64(long[:len(long)-1]) {
+		t.Error("XXHash64 ignores trailing bytes")
+	}
+}
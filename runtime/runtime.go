@@ -0,0 +1,82 @@
+// Package runtime is the registry of the bundles of files generated by bindata
+// with -register, so that applications composed of several packages embedding
+// files can enumerate and serve all of them uniformly.
+//
+// Each generated file registers its bundle when its package is initialized:
+//
+//	for _, b := range runtime.Bundles() {
+//		fmt.Println(b.Name, len(b.Names))
+//	}
+//	http.Handle("/assets/", http.StripPrefix("/assets/", runtime.Handler()))
+package runtime
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Bundle is a set of embedded files registered under a name.
+type Bundle struct {
+	Name  string                           // name of the bundle, unique in the registry
+	Names []string                         // names of the files, sorted
+	Read  func(name string) ([]byte, bool) // copy of the contents of the named file, or false if there is no such file
+}
+
+var (
+	mu      sync.RWMutex
+	bundles = make(map[string]Bundle)
+)
+
+// Register adds a bundle to the registry.
+// It panics if a bundle with the same name is already registered.
+func Register(b Bundle) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := bundles[b.Name]; ok {
+		panic("bindata/runtime: Register called twice for bundle " + b.Name)
+	}
+	bundles[b.Name] = b
+}
+
+// Lookup returns the bundle registered under name, or false if there is none.
+func Lookup(name string) (Bundle, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	b, ok := bundles[name]
+	return b, ok
+}
+
+// Bundles returns the registered bundles sorted by name.
+func Bundles() []Bundle {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]Bundle, 0, len(bundles))
+	for _, b := range bundles {
+		list = append(list, b)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Handler returns an HTTP handler serving the files of all the bundles,
+// the file name of bundle b at the path /b/name.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bundle, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		b, ok := Lookup(bundle)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data, ok := b.Read(name)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+	})
+}
@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// bundle returns a bundle of the given files.
+func bundle(name string, files map[string]string) Bundle {
+	b := Bundle{Name: name, Read: func(name string) ([]byte, bool) {
+		data, ok := files[name]
+		return []byte(data), ok
+	}}
+	for name := range files {
+		b.Names = append(b.Names, name)
+	}
+	return b
+}
+
+// TestRegistry tests registering, enumerating and serving bundles.
+func TestRegistry(t *testing.T) {
+	defer func(orig map[string]Bundle) { bundles = orig }(bundles)
+	bundles = make(map[string]Bundle)
+	Register(bundle("web", map[string]string{"index.html": "<html>"}))
+	Register(bundle("sql", map[string]string{"1_init.up.sql": "CREATE TABLE t;"}))
+
+	list := Bundles()
+	if len(list) != 2 || list[0].Name != "sql" || list[1].Name != "web" {
+		t.Errorf("unexpected bundles %v", list)
+	}
+	if _, ok := Lookup("missing"); ok {
+		t.Error("unexpected bundle missing")
+	}
+
+	tests := map[string]int{
+		"/web/index.html":   http.StatusOK,
+		"/web/missing.html": http.StatusNotFound,
+		"/missing/file":     http.StatusNotFound,
+	}
+	for path, code := range tests {
+		w := httptest.NewRecorder()
+		Handler().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != code {
+			t.Errorf("%s: expected status %d, got %d", path, code, w.Code)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for duplicate bundle")
+		}
+	}()
+	Register(bundle("web", nil))
+}